@@ -14,7 +14,8 @@ import (
 
 	"github.com/koustreak/DatRi/internal/config"
 	"github.com/koustreak/DatRi/internal/database"
-	"github.com/koustreak/DatRi/internal/database/postgres"
+	_ "github.com/koustreak/DatRi/internal/database/mysql"
+	_ "github.com/koustreak/DatRi/internal/database/postgres"
 	"github.com/koustreak/DatRi/internal/server/rest"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -100,15 +101,7 @@ func run() error {
 func connectAndInspect(ctx context.Context, res config.ResourceConfig, logger zerolog.Logger) (database.DB, *database.Schema, error) {
 	dbCfg := res.Database.ToDatabaseConfig()
 
-	var db database.DB
-	var err error
-
-	switch dbCfg.Driver {
-	case database.DriverPostgres:
-		db, err = postgres.New(ctx, dbCfg)
-	default:
-		return nil, nil, fmt.Errorf("unsupported driver: %q (mysql support coming soon)", dbCfg.Driver)
-	}
+	db, err := database.Open(ctx, dbCfg)
 	if err != nil {
 		return nil, nil, fmt.Errorf("connecting to database: %w", err)
 	}
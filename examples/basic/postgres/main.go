@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/koustreak/DatRi/internal/database"
@@ -97,7 +98,8 @@ func main() {
 		}
 		if len(table.ForeignKeys) > 0 {
 			for _, fk := range table.ForeignKeys {
-				fmt.Printf("    FK: %s → %s.%s\n", fk.Column, fk.RefTable, fk.RefColumn)
+				fmt.Printf("    FK: (%s) → %s(%s)\n",
+					strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", "))
 			}
 		}
 		fmt.Println()
@@ -0,0 +1,154 @@
+// Package clock abstracts reading the time and waiting, so that TTL,
+// expiry, backoff and cooldown logic can be driven deterministically.
+//
+// Production code holds a Clock, defaulting to Real; tests substitute a
+// Fake and move it forward with Advance:
+//
+//	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	codec := database.CursorCodec{TTL: time.Minute, Clock: clk}
+//	cursor, _ := codec.Encode(keys)
+//	clk.Advance(2 * time.Minute)
+//	_, err := codec.Decode(cursor) // expired
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and makes timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer returns a Timer that fires once, d from now.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-shot timer, like *time.Timer.
+type Timer interface {
+	// C returns the channel the time is delivered on when the timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing. It reports whether it was still
+	// pending.
+	Stop() bool
+}
+
+// Real is the Clock backed by package time.
+var Real Clock = realClock{}
+
+// Or returns c, or Real if c is nil, for optional Clock fields.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+func (t realTimer) Stop() bool          { return t.t.Stop() }
+
+// Fake is a Clock that only moves when told to. Timers fire during
+// Advance or Set, in deadline order. It is safe for concurrent use.
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake returns a Fake showing now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTimer returns a Timer that fires once the fake time reaches now + d.
+// A timer with d <= 0 fires immediately.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := &fakeTimer{f: f, at: f.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.ch <- f.now
+		return t
+	}
+	f.timers = append(f.timers, t)
+	return t
+}
+
+// Advance moves the fake time forward by d, firing timers that come due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(f.now.Add(d))
+}
+
+// Set moves the fake time to t, firing timers that come due. Time may
+// move backwards; no timers fire then.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(t)
+}
+
+// Pending returns the number of timers waiting to fire. Tests use it to
+// wait until the code under test is blocked on a timer before advancing.
+func (f *Fake) Pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.timers)
+}
+
+func (f *Fake) setLocked(now time.Time) {
+	f.now = now
+	sort.SliceStable(f.timers, func(i, j int) bool { return f.timers[i].at.Before(f.timers[j].at) })
+
+	n := 0
+	for _, t := range f.timers {
+		if t.at.After(now) {
+			f.timers[n] = t
+			n++
+			continue
+		}
+		t.ch <- t.at
+	}
+	clear(f.timers[n:])
+	f.timers = f.timers[:n]
+}
+
+type fakeTimer struct {
+	f  *Fake
+	at time.Time
+	ch chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+	for i, p := range t.f.timers {
+		if p == t {
+			t.f.timers = append(t.f.timers[:i], t.f.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
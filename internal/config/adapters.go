@@ -1,6 +1,8 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/koustreak/DatRi/internal/database"
 	"github.com/koustreak/DatRi/internal/filestore"
 )
@@ -18,6 +20,10 @@ func (d *DatabaseConfig) ToDatabaseConfig() *database.Config {
 		MaxConnIdleTime: d.Pool.MaxConnIdleTime,
 		ConnectTimeout:  d.Timeouts.Connect,
 		QueryTimeout:    d.Timeouts.Query,
+		AcquireTimeout:  d.Timeouts.Acquire,
+		ProbeQuery:      d.ProbeQuery,
+		AutoReconnect:   d.Pool.AutoReconnect,
+		ReconnectWindow: d.Pool.ReconnectWindow,
 	}
 }
 
@@ -26,12 +32,16 @@ func (d *DatabaseConfig) ToDatabaseConfig() *database.Config {
 // Only call this when ResourceConfig.Type == "filestore".
 func (f *FilestoreConfig) ToFilestoreConfig() *filestore.Config {
 	return &filestore.Config{
-		Provider:      filestore.Provider(f.Provider),
-		Endpoint:      f.Endpoint,
-		AccessKey:     f.AccessKey,
-		SecretKey:     f.SecretKey,
-		UseSSL:        f.UseSSL,
-		Region:        f.Region,
-		DefaultBucket: f.DefaultBucket,
+		Provider:        filestore.Provider(f.Provider),
+		Endpoint:        f.Endpoint,
+		Credentials:     filestore.CredentialSource(strings.ToLower(f.Credentials)),
+		AccessKey:       f.AccessKey,
+		SecretKey:       f.SecretKey,
+		Profile:         f.Profile,
+		UseSSL:          f.UseSSL,
+		Region:          f.Region,
+		DefaultBucket:   f.DefaultBucket,
+		ProjectID:       f.ProjectID,
+		CredentialsFile: f.CredentialsFile,
 	}
 }
@@ -140,6 +140,10 @@ type DatabaseConfig struct {
 
 	// Timeouts controls per-operation deadlines.
 	Timeouts TimeoutConfig `yaml:"timeouts"`
+
+	// ProbeQuery is the cheap statement run by readiness probes to confirm
+	// the database can serve queries. Default: "SELECT 1"
+	ProbeQuery string `yaml:"probe_query"`
 }
 
 // PoolConfig controls the database connection pool behaviour.
@@ -155,6 +159,14 @@ type PoolConfig struct {
 
 	// MaxConnIdleTime is how long a connection may sit idle. Default: 5m
 	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+
+	// AutoReconnect recreates the pool after all connections are lost,
+	// e.g. on a database failover. Default: false
+	AutoReconnect bool `yaml:"auto_reconnect"`
+
+	// ReconnectWindow is how long to keep trying to recreate the pool.
+	// Default: 30s
+	ReconnectWindow time.Duration `yaml:"reconnect_window"`
 }
 
 // TimeoutConfig controls per-operation deadlines.
@@ -164,27 +176,39 @@ type TimeoutConfig struct {
 
 	// Query is the default per-query deadline. Default: 30s
 	Query time.Duration `yaml:"query"`
+
+	// Acquire is how long a query may wait for a free pooled connection.
+	// Default: 0 (no limit beyond the query deadline)
+	Acquire time.Duration `yaml:"acquire"`
 }
 
 // ─── Filestore ────────────────────────────────────────────────────────────────
 
 // FilestoreConfig holds all settings needed to connect to an object storage backend.
 type FilestoreConfig struct {
-	// Provider is the storage backend. Allowed: "minio", "s3"
+	// Provider is the storage backend. Allowed: "minio", "s3", "gcs"
 	Provider string `yaml:"provider"`
 
 	// Endpoint is the host:port of the storage server.
 	// Example: "localhost:9000" for local MinIO.
 	Endpoint string `yaml:"endpoint"`
 
-	// AccessKey is the access key ID (MinIO / S3 style).
+	// Credentials selects where credentials come from.
+	// Allowed: "static", "env", "iam_role", "profile". Default: "static"
+	Credentials string `yaml:"credentials"`
+
+	// AccessKey is the access key ID (MinIO / S3 style). Static credentials only.
 	// Use env var DATRI_RESOURCE_<NAME>_ACCESS_KEY to avoid storing in yaml.
 	AccessKey string `yaml:"access_key"`
 
-	// SecretKey is the secret access key.
+	// SecretKey is the secret access key. Static credentials only.
 	// Use env var DATRI_RESOURCE_<NAME>_SECRET_KEY to avoid storing in yaml.
 	SecretKey string `yaml:"secret_key"`
 
+	// Profile is the AWS shared-credentials profile read when Credentials
+	// is "profile". Default: $AWS_PROFILE, else "default"
+	Profile string `yaml:"profile"`
+
 	// UseSSL controls whether TLS is used for the connection. Default: false
 	UseSSL bool `yaml:"use_ssl"`
 
@@ -192,6 +216,14 @@ type FilestoreConfig struct {
 	// Leave empty for MinIO.
 	Region string `yaml:"region"`
 
+	// ProjectID is the Google Cloud project to list buckets from. GCS only.
+	ProjectID string `yaml:"project_id"`
+
+	// CredentialsFile is the path to a GCS service-account key JSON file
+	// (leave empty to use Application Default Credentials), or the AWS
+	// shared credentials file when Credentials is "profile".
+	CredentialsFile string `yaml:"credentials_file"`
+
 	// DefaultBucket is an optional default bucket name.
 	DefaultBucket string `yaml:"default_bucket"`
 }
@@ -32,6 +32,14 @@ var validAuthTypes = map[string]bool{
 var validFilestoreProviders = map[string]bool{
 	"minio": true,
 	"s3":    true,
+	"gcs":   true,
+}
+
+var validCredentialSources = map[string]bool{
+	"static":   true,
+	"env":      true,
+	"iam_role": true,
+	"profile":  true,
 }
 
 var validLogLevels = map[string]bool{
@@ -208,11 +216,30 @@ func validateDatabase(loc func(string) string, d *DatabaseConfig) error {
 
 func validateFilestore(loc func(string) string, f *FilestoreConfig) error {
 	if !validFilestoreProviders[strings.ToLower(f.Provider)] {
-		return fmt.Errorf("%s provider %q is not supported (allowed: minio, s3)", loc("filestore.provider"), f.Provider)
+		return fmt.Errorf("%s provider %q is not supported (allowed: minio, s3, gcs)", loc("filestore.provider"), f.Provider)
+	}
+	creds := strings.ToLower(f.Credentials)
+	if creds == "" {
+		creds = "static"
+	}
+	if !validCredentialSources[creds] {
+		return fmt.Errorf("%s %q is not supported (allowed: static, env, iam_role, profile)", loc("filestore.credentials"), f.Credentials)
+	}
+	if strings.EqualFold(f.Provider, "gcs") {
+		// GCS authenticates with a key file or Application Default
+		// Credentials, not an endpoint and access keys.
+		if strings.TrimSpace(f.ProjectID) == "" && strings.TrimSpace(f.DefaultBucket) == "" {
+			return fmt.Errorf("%s or %s is required for gcs", loc("filestore.project_id"), loc("filestore.default_bucket"))
+		}
+		return nil
 	}
 	if strings.TrimSpace(f.Endpoint) == "" {
 		return fmt.Errorf("%s is required", loc("filestore.endpoint"))
 	}
+	if creds != "static" {
+		// Keys come from the environment, the instance role or a profile.
+		return nil
+	}
 	if strings.TrimSpace(f.AccessKey) == "" {
 		return fmt.Errorf("%s is required", loc("filestore.access_key"))
 	}
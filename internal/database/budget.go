@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+type queryBudgetKey struct{}
+
+// queryBudget counts the statements issued under one WithBudget context.
+type queryBudget struct {
+	max  int64
+	used atomic.Int64
+}
+
+// WithBudget returns a context that allows at most maxQueries statements
+// (Query, QueryRow and ExecResult, on the DB or a Tx). Further statements
+// fail with ErrKindInvalidInput before reaching the database, so an N+1
+// loop in a handler shows up as an error in tests and staging instead of
+// as latency in production:
+//
+//	ctx = database.WithBudget(ctx, 10)
+//	rows, err := db.Query(ctx, …) // the 11th statement fails
+//
+// The count is shared by every goroutine using ctx or a context derived
+// from it. A nested WithBudget starts a fresh count for its own subtree.
+// Without a budget, statements are not counted.
+func WithBudget(ctx context.Context, maxQueries int) context.Context {
+	return context.WithValue(ctx, queryBudgetKey{}, &queryBudget{max: int64(maxQueries)})
+}
+
+// BudgetUsed returns how many statements have been charged to ctx's
+// budget, or 0 if it has none. Tests use it to assert a code path's query
+// count.
+func BudgetUsed(ctx context.Context) int {
+	b, _ := ctx.Value(queryBudgetKey{}).(*queryBudget)
+	if b == nil {
+		return 0
+	}
+	return int(b.used.Load())
+}
+
+// ChargeBudget counts one statement against ctx's budget and returns an
+// ErrKindInvalidInput error once the budget is exceeded. Drivers call it
+// on every statement they send; without a budget it does nothing.
+func ChargeBudget(ctx context.Context) error {
+	b, _ := ctx.Value(queryBudgetKey{}).(*queryBudget)
+	if b == nil {
+		return nil
+	}
+	if n := b.used.Add(1); n > b.max {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("query budget exceeded: statement %d of at most %d", n, b.max))
+	}
+	return nil
+}
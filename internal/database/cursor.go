@@ -0,0 +1,120 @@
+package database
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/clock"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// CursorCodec turns the key values of the last row on a page into an
+// opaque cursor string for a public API, and back, for use with After:
+//
+//	codec := CursorCodec{Secret: key, TTL: time.Hour}
+//	next, err := codec.Encode(map[string]any{"id": lastRow["id"]})
+//	// … on the next request …
+//	keys, err := codec.Decode(r.URL.Query().Get("cursor"))
+//	q := Select("users", DialectPostgres).After("id", keys["id"]).Limit(20)
+//
+// A cursor is base64url-encoded JSON, so it hides nothing from a
+// determined reader; Secret makes it tamper-proof, not confidential.
+// Decoded numbers are int64 when integral, else float64; times come back
+// as RFC 3339 strings, which both engines accept for time columns.
+type CursorCodec struct {
+	// Secret, if set, signs cursors with HMAC-SHA256 so Decode rejects any
+	// cursor this codec did not produce. Keep it stable across instances.
+	Secret []byte
+
+	// TTL, if set, makes cursors expire that long after Encode.
+	TTL time.Duration
+
+	// Clock is read for TTL expiry. Nil means clock.Real.
+	Clock clock.Clock
+}
+
+// cursorPayload is the JSON inside a cursor.
+type cursorPayload struct {
+	Keys    map[string]any `json:"k"`
+	Expires int64          `json:"e,omitempty"` // unix seconds; 0: never
+}
+
+var cursorEncoding = base64.RawURLEncoding
+
+// EncodeCursor encodes keys as an unsigned cursor that never expires.
+// See CursorCodec.
+func EncodeCursor(keys map[string]any) (string, error) {
+	return CursorCodec{}.Encode(keys)
+}
+
+// DecodeCursor decodes a cursor made by EncodeCursor. See CursorCodec.
+func DecodeCursor(cursor string) (map[string]any, error) {
+	return CursorCodec{}.Decode(cursor)
+}
+
+// Encode returns the cursor for keys. Values must be JSON-encodable.
+func (c CursorCodec) Encode(keys map[string]any) (string, error) {
+	p := cursorPayload{Keys: keys}
+	if c.TTL > 0 {
+		p.Expires = clock.Or(c.Clock).Now().Add(c.TTL).Unix()
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", errs.Wrap(errs.ErrKindInvalidInput, "cursor keys are not JSON-encodable", err)
+	}
+
+	cursor := cursorEncoding.EncodeToString(data)
+	if len(c.Secret) > 0 {
+		cursor += "." + cursorEncoding.EncodeToString(c.sign(data))
+	}
+	return cursor, nil
+}
+
+// Decode returns the keys in cursor. A malformed, tampered, unsigned (when
+// Secret is set) or expired cursor fails with ErrKindInvalidInput.
+func (c CursorCodec) Decode(cursor string) (map[string]any, error) {
+	body, sig, signed := strings.Cut(cursor, ".")
+	data, err := cursorEncoding.DecodeString(body)
+	if err != nil || (signed && len(c.Secret) == 0) {
+		return nil, errs.Wrap(errs.ErrKindInvalidInput, "malformed cursor", err)
+	}
+
+	if len(c.Secret) > 0 {
+		mac, err := cursorEncoding.DecodeString(sig)
+		if !signed || err != nil || !hmac.Equal(mac, c.sign(data)) {
+			return nil, errs.New(errs.ErrKindInvalidInput, "invalid cursor signature")
+		}
+	}
+
+	var p cursorPayload
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&p); err != nil || p.Keys == nil {
+		return nil, errs.Wrap(errs.ErrKindInvalidInput, "malformed cursor", err)
+	}
+	if p.Expires != 0 && clock.Or(c.Clock).Now().Unix() > p.Expires {
+		return nil, errs.New(errs.ErrKindInvalidInput, "cursor has expired")
+	}
+
+	for k, v := range p.Keys {
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				p.Keys[k] = i
+			} else {
+				p.Keys[k], _ = n.Float64()
+			}
+		}
+	}
+	return p.Keys, nil
+}
+
+func (c CursorCodec) sign(data []byte) []byte {
+	h := hmac.New(sha256.New, c.Secret)
+	h.Write(data)
+	return h.Sum(nil)
+}
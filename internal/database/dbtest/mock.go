@@ -0,0 +1,318 @@
+// Package dbtest provides in-memory test doubles for database.DB, so code
+// that consumes DatRi can be unit tested without a running database.
+//
+//	db := dbtest.New(database.DialectPostgres)
+//	db.ExpectQuery(`FROM "users"`).Return([]string{"id", "name"},
+//	    []any{int64(1), "alice"},
+//	)
+//	// … exercise the code under test with db …
+//	if err := db.ExpectationsMet(); err != nil {
+//	    t.Fatal(err)
+//	}
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// Call records one Query, QueryRow or ExecResult made against a MockDB.
+type Call struct {
+	SQL  string
+	Args []any
+}
+
+// MockDB is an in-memory database.DB that answers queries from registered
+// expectations. It is safe for concurrent use by multiple goroutines.
+type MockDB struct {
+	// Schema backs ListTables, TableExists and the Inspect methods.
+	// A nil Schema behaves like an empty database.
+	Schema *database.Schema
+
+	// PingErr is returned by Ping.
+	PingErr error
+
+	// ProbeErr is returned by Probe.
+	ProbeErr error
+
+	dialect database.Dialect
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+	closed       bool
+}
+
+var _ database.DB = (*MockDB)(nil)
+
+// New returns an empty MockDB reporting the given dialect.
+func New(d database.Dialect) *MockDB {
+	return &MockDB{dialect: d}
+}
+
+// Expectation is a canned response for queries matching a pattern.
+// Configure it with Return, ReturnResult or ReturnError.
+type Expectation struct {
+	pattern *regexp.Regexp
+	columns []string
+	rows    [][]any
+	result  database.Result
+	err     error
+	calls   int
+}
+
+// ExpectQuery registers a response for every query whose SQL matches the
+// regular expression pattern. Expectations are tried in registration order
+// and may match any number of times. It panics if pattern does not compile.
+func (m *MockDB) ExpectQuery(pattern string) *Expectation {
+	e := &Expectation{pattern: regexp.MustCompile(pattern)}
+	m.mu.Lock()
+	m.expectations = append(m.expectations, e)
+	m.mu.Unlock()
+	return e
+}
+
+// Return makes matching queries return the given columns and rows.
+// Each row must have one value per column.
+func (e *Expectation) Return(columns []string, rows ...[]any) *Expectation {
+	e.columns = columns
+	e.rows = rows
+	return e
+}
+
+// ReturnResult makes matching ExecResult calls report r.
+func (e *Expectation) ReturnResult(r database.Result) *Expectation {
+	e.result = r
+	return e
+}
+
+// ReturnError makes matching queries fail with err.
+func (e *Expectation) ReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// Calls returns every query made so far, in order.
+func (m *MockDB) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// ExpectationsMet returns an error listing the expectations that no query
+// has matched.
+func (m *MockDB) ExpectationsMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var unmet []string
+	for _, e := range m.expectations {
+		if e.calls == 0 {
+			unmet = append(unmet, e.pattern.String())
+		}
+	}
+	if len(unmet) > 0 {
+		return fmt.Errorf("dbtest: expected queries were never run: %s", strings.Join(unmet, ", "))
+	}
+	return nil
+}
+
+// --- database.DB implementation ---
+
+func (m *MockDB) Ping(ctx context.Context) error { return m.PingErr }
+
+func (m *MockDB) Probe(ctx context.Context) error { return m.ProbeErr }
+
+func (m *MockDB) Close() {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+}
+
+func (m *MockDB) Query(ctx context.Context, sql string, args ...any) (database.Rows, error) {
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	return m.query(sql, args)
+}
+
+// QueryRow returns the first row of the matching expectation. As with the
+// real drivers, an empty result surfaces as ErrKindNotFound from Scan.
+func (m *MockDB) QueryRow(ctx context.Context, sql string, args ...any) (database.Row, error) {
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := m.query(sql, args)
+	if err != nil {
+		return &mockRow{err: err}, nil
+	}
+	return &mockRow{rows: rows}, nil
+}
+
+// ExecResult reports the Result set with ReturnResult on the matching
+// expectation, or a zero Result.
+func (m *MockDB) ExecResult(ctx context.Context, sql string, args ...any) (*database.Result, error) {
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, err := m.match(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	res := e.result
+	return &res, nil
+}
+
+func (m *MockDB) ListTables(ctx context.Context) ([]string, error) {
+	tables := make([]string, 0)
+	if m.Schema != nil {
+		for name := range m.Schema.Tables {
+			tables = append(tables, name)
+		}
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+func (m *MockDB) TableExists(ctx context.Context, table string) (bool, error) {
+	if m.Schema == nil {
+		return false, nil
+	}
+	_, ok := m.Schema.Tables[table]
+	return ok, nil
+}
+
+func (m *MockDB) InspectSchema(ctx context.Context) (*database.Schema, error) {
+	if m.Schema == nil {
+		return &database.Schema{Tables: map[string]*database.TableInfo{}}, nil
+	}
+	return m.Schema, nil
+}
+
+// InspectSchemaWith returns the tables of Schema that opts selects.
+func (m *MockDB) InspectSchemaWith(ctx context.Context, opts database.InspectOptions) (*database.Schema, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	out := &database.Schema{Tables: map[string]*database.TableInfo{}}
+	if m.Schema != nil {
+		for name, t := range m.Schema.Tables {
+			if opts.Matches(name) {
+				out.Tables[name] = t
+			}
+		}
+	}
+	return out, nil
+}
+
+// InspectTable returns the table from Schema, or ErrKindNotFound.
+func (m *MockDB) InspectTable(ctx context.Context, table string) (*database.TableInfo, error) {
+	if m.Schema != nil {
+		if info, ok := m.Schema.Tables[table]; ok {
+			return info, nil
+		}
+	}
+	return nil, errs.New(errs.ErrKindNotFound, fmt.Sprintf("table %q does not exist", table))
+}
+
+// Begin returns a transaction whose queries are answered by m.
+func (m *MockDB) Begin(ctx context.Context) (database.Tx, error) {
+	return &mockTx{db: m}, nil
+}
+
+func (m *MockDB) BeginTx(ctx context.Context, opts database.TxOptions) (database.Tx, error) {
+	return &mockTx{db: m}, nil
+}
+
+func (m *MockDB) Dialect() database.Dialect { return m.dialect }
+
+// query records the call and answers it from the first matching expectation.
+func (m *MockDB) query(sql string, args []any) (*MockRows, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, err := m.match(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return NewRows(e.columns, e.rows...), nil
+}
+
+// match records the call and returns the first matching expectation, or
+// the error the call should fail with. m.mu must be held.
+func (m *MockDB) match(sql string, args []any) (*Expectation, error) {
+	if m.closed {
+		return nil, errs.New(errs.ErrKindConnectionFailed, "dbtest: query on closed MockDB")
+	}
+	m.calls = append(m.calls, Call{SQL: sql, Args: args})
+
+	for _, e := range m.expectations {
+		if !e.pattern.MatchString(sql) {
+			continue
+		}
+		e.calls++
+		if e.err != nil {
+			return nil, e.err
+		}
+		return e, nil
+	}
+	return nil, errs.New(errs.ErrKindQueryFailed, fmt.Sprintf("dbtest: unexpected query: %s", sql))
+}
+
+// mockTx answers queries through its MockDB; Commit and Rollback are no-ops.
+type mockTx struct {
+	db *MockDB
+}
+
+func (t *mockTx) Query(ctx context.Context, sql string, args ...any) (database.Rows, error) {
+	return t.db.Query(ctx, sql, args...)
+}
+
+func (t *mockTx) QueryRow(ctx context.Context, sql string, args ...any) (database.Row, error) {
+	return t.db.QueryRow(ctx, sql, args...)
+}
+
+func (t *mockTx) ExecResult(ctx context.Context, sql string, args ...any) (*database.Result, error) {
+	return t.db.ExecResult(ctx, sql, args...)
+}
+
+func (t *mockTx) Commit(ctx context.Context) error   { return nil }
+func (t *mockTx) Rollback(ctx context.Context) error { return nil }
+
+func (t *mockTx) Savepoint(ctx context.Context, name string) error {
+	return database.ValidateSavepointName(name)
+}
+
+func (t *mockTx) RollbackTo(ctx context.Context, name string) error {
+	return database.ValidateSavepointName(name)
+}
+
+func (t *mockTx) ReleaseSavepoint(ctx context.Context, name string) error {
+	return database.ValidateSavepointName(name)
+}
+
+type mockRow struct {
+	rows *MockRows
+	err  error
+}
+
+func (r *mockRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		return errs.New(errs.ErrKindNotFound, "no rows found")
+	}
+	return r.rows.Scan(dest...)
+}
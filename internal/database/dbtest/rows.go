@@ -0,0 +1,194 @@
+package dbtest
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// MockRows is an in-memory database.Rows over a fixed set of rows.
+type MockRows struct {
+	columns []string
+	types   []database.ColumnType
+	rows    [][]any
+	pos     int // index of the current row + 1; 0 before the first Next
+	err     error
+	closed  bool
+}
+
+var _ database.Rows = (*MockRows)(nil)
+
+// NewRows returns rows with the given columns and values. Each row must
+// have one value per column; nil stands for NULL.
+func NewRows(columns []string, rows ...[]any) *MockRows {
+	return &MockRows{columns: columns, rows: rows}
+}
+
+// WithColumnTypes sets the database type names ColumnTypes reports, one per
+// column, e.g. "INT8" or "TEXT". Without it every DatabaseType is empty.
+func (r *MockRows) WithColumnTypes(dbTypes ...string) *MockRows {
+	r.types = make([]database.ColumnType, len(r.columns))
+	for i, c := range r.columns {
+		r.types[i].Name = c
+		if i < len(dbTypes) {
+			r.types[i].DatabaseType = dbTypes[i]
+		}
+	}
+	return r
+}
+
+// WithErr makes Err return err once the rows are exhausted, simulating a
+// failure part-way through iteration.
+func (r *MockRows) WithErr(err error) *MockRows {
+	r.err = err
+	return r
+}
+
+func (r *MockRows) Next() bool {
+	if r.closed || r.pos >= len(r.rows) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+// Scan copies the current row into dest. Each destination is a *any, an
+// sql.Scanner, or a pointer to a type the value is assignable to. Beyond
+// that, like database/sql, numbers convert to other numeric types they
+// fit in, strings and []byte convert to each other, and numbers scan into
+// text as their decimal form; anything else is an error. NULL sets the
+// zero value.
+func (r *MockRows) Scan(dest ...any) error {
+	if r.pos == 0 || r.closed {
+		return errs.New(errs.ErrKindQueryFailed, "dbtest: Scan called without a current row")
+	}
+	row := r.rows[r.pos-1]
+	if len(dest) != len(row) {
+		return errs.New(errs.ErrKindQueryFailed,
+			fmt.Sprintf("dbtest: Scan expected %d destination(s), got %d", len(row), len(dest)))
+	}
+	for i, d := range dest {
+		if err := assign(d, row[i]); err != nil {
+			return errs.Wrap(errs.ErrKindQueryFailed, fmt.Sprintf("dbtest: column %d", i), err)
+		}
+	}
+	return nil
+}
+
+func (r *MockRows) Close() { r.closed = true }
+
+func (r *MockRows) Err() error {
+	if r.pos >= len(r.rows) {
+		return r.err
+	}
+	return nil
+}
+
+func (r *MockRows) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+func (r *MockRows) ColumnTypes() ([]database.ColumnType, error) {
+	if r.types == nil {
+		r.WithColumnTypes()
+	}
+	return r.types, nil
+}
+
+// assign stores v into the pointer dest.
+func assign(dest, v any) error {
+	if p, ok := dest.(*any); ok {
+		*p = v
+		return nil
+	}
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(v)
+	}
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dest)
+	}
+	target := dv.Elem()
+	if v == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	sv := reflect.ValueOf(v)
+	switch {
+	case sv.Type().AssignableTo(target.Type()):
+		target.Set(sv)
+	case isNumeric(sv.Kind()) && isNumeric(target.Kind()):
+		if overflows(target, sv) {
+			return fmt.Errorf("value %v overflows %s", v, target.Type())
+		}
+		target.Set(sv.Convert(target.Type()))
+	case isText(sv.Type()) && isText(target.Type()):
+		target.Set(sv.Convert(target.Type()))
+	case isNumeric(sv.Kind()) && isText(target.Type()):
+		// Numbers scan into text as their decimal form, as with
+		// database/sql, never as a rune.
+		target.Set(reflect.ValueOf(formatNumber(sv)).Convert(target.Type()))
+	default:
+		return fmt.Errorf("cannot scan %T into %T", v, dest)
+	}
+	return nil
+}
+
+func isNumeric(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64 && k != reflect.Uintptr
+}
+
+// isText reports whether t is a string or a byte slice kind.
+func isText(t reflect.Type) bool {
+	return t.Kind() == reflect.String || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8)
+}
+
+// overflows reports whether the numeric sv does not fit target.
+func overflows(target, sv reflect.Value) bool {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch sv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return target.OverflowInt(sv.Int())
+		case reflect.Float32, reflect.Float64:
+			f := sv.Float()
+			return f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 || target.OverflowInt(int64(f))
+		default:
+			return sv.Uint() > math.MaxInt64 || target.OverflowInt(int64(sv.Uint()))
+		}
+	case reflect.Float32, reflect.Float64:
+		return target.OverflowFloat(sv.Convert(target.Type()).Float())
+	default:
+		switch sv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return sv.Int() < 0 || target.OverflowUint(uint64(sv.Int()))
+		case reflect.Float32, reflect.Float64:
+			f := sv.Float()
+			return f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 || target.OverflowUint(uint64(f))
+		default:
+			return target.OverflowUint(sv.Uint())
+		}
+	}
+}
+
+// formatNumber renders a numeric value the way database/sql does when
+// scanning it into a string.
+func formatNumber(sv reflect.Value) string {
+	switch sv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(sv.Int(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(sv.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(sv.Float(), 'g', -1, 64)
+	default:
+		return strconv.FormatUint(sv.Uint(), 10)
+	}
+}
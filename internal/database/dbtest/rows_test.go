@@ -0,0 +1,52 @@
+package dbtest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMockRowsScanConversions(t *testing.T) {
+	rows := NewRows([]string{"n", "f", "b", "s", "big"},
+		[]any{int64(1), 2.5, []byte("bytes"), "text", int64(300)})
+	if !rows.Next() {
+		t.Fatal("Next returned false")
+	}
+
+	var n string
+	var f string
+	var b string
+	var s []byte
+	var small int32
+	if err := rows.Scan(&n, &f, &b, &s, &small); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if n != "1" || f != "2.5" || b != "bytes" || string(s) != "text" || small != 300 {
+		t.Errorf("Scan = %q, %q, %q, %q, %d", n, f, b, s, small)
+	}
+}
+
+func TestMockRowsScanRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		dest    any
+		wantErr string
+	}{
+		{"overflow", int64(300), new(int8), "overflows"},
+		{"negative into unsigned", int64(-1), new(uint), "overflows"},
+		{"fraction into int", 1.5, new(int64), "overflows"},
+		{"bool into string", true, new(string), "cannot scan"},
+		{"string into int", "1", new(int64), "cannot scan"},
+		{"int into bool", int64(1), new(bool), "cannot scan"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := NewRows([]string{"v"}, []any{tt.value})
+			rows.Next()
+			err := rows.Scan(tt.dest)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Scan error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
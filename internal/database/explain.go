@@ -0,0 +1,175 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// Explain returns the query plan for sql without executing it, using
+// EXPLAIN on Postgres and EXPLAIN FORMAT=TREE on MySQL 8.
+//
+//	q, args, _ := Select("orders", db.Dialect()).Where("user_id", "=", 7).Build()
+//	plan, err := Explain(ctx, db, q, args...)
+func Explain(ctx context.Context, db DB, sql string, args ...any) (string, error) {
+	prefix := "EXPLAIN "
+	if db.Dialect() == DialectMySQL {
+		prefix = "EXPLAIN FORMAT=TREE "
+	}
+	return explain(ctx, db, prefix+sql, args)
+}
+
+// ExplainAnalyze is like Explain but executes the statement and reports
+// actual row counts and timings. The statement really runs — including
+// any writes it performs — so wrap it in a transaction that is rolled back
+// when explaining an INSERT, UPDATE or DELETE.
+//
+// On MySQL this requires 8.0.18 or later.
+func ExplainAnalyze(ctx context.Context, db DB, sql string, args ...any) (string, error) {
+	return explain(ctx, db, "EXPLAIN ANALYZE "+sql, args)
+}
+
+// explain runs an EXPLAIN statement and joins the plan lines. Postgres
+// returns one row per plan line; MySQL's tree format returns a single row.
+func explain(ctx context.Context, db DB, sql string, args []any) (string, error) {
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", wrapError("failed to scan query plan", err)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", wrapError("failed to read query plan", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// CostEstimate is the planner's estimate for a query, as reported by
+// EXPLAIN without running it.
+type CostEstimate struct {
+	// Cost is the planner's total cost, in the engine's own units
+	// (Postgres: "Total Cost"; MySQL: "query_cost"). Compare it only with
+	// other estimates from the same server.
+	Cost float64 `json:"cost"`
+
+	// Rows is the estimated number of rows the query returns.
+	Rows int64 `json:"rows"`
+}
+
+// EstimateCost builds b and asks db's planner what it would cost, without
+// executing it. A handler can use it to refuse expensive user-built
+// queries before running them:
+//
+//	est, err := q.EstimateCost(ctx, db)
+//	if err == nil && est.Rows > 100_000 { ... reject ... }
+//
+// Estimates come from table statistics and can be far off for stale
+// statistics or complex joins; treat them as a coarse guard.
+func (b *SelectBuilder) EstimateCost(ctx context.Context, db DB) (*CostEstimate, error) {
+	sql, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := "EXPLAIN (FORMAT JSON) "
+	if db.Dialect() == DialectMySQL {
+		prefix = "EXPLAIN FORMAT=JSON "
+	}
+	row, err := db.QueryRow(ctx, prefix+sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	var plan []byte
+	if err := row.Scan(&plan); err != nil {
+		return nil, wrapError("failed to scan query plan", err)
+	}
+
+	if db.Dialect() == DialectMySQL {
+		return parseMySQLPlan(plan)
+	}
+	return parsePostgresPlan(plan)
+}
+
+// parsePostgresPlan reads the root node of EXPLAIN (FORMAT JSON) output:
+//
+//	[{"Plan": {"Total Cost": 35.5, "Plan Rows": 1000, ...}}]
+func parsePostgresPlan(plan []byte) (*CostEstimate, error) {
+	var out []struct {
+		Plan struct {
+			TotalCost float64 `json:"Total Cost"`
+			PlanRows  float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &out); err != nil || len(out) == 0 {
+		return nil, errs.Wrap(errs.ErrKindQueryFailed, "failed to parse query plan", err)
+	}
+	return &CostEstimate{Cost: out[0].Plan.TotalCost, Rows: int64(out[0].Plan.PlanRows)}, nil
+}
+
+// parseMySQLPlan reads EXPLAIN FORMAT=JSON output. The cost is
+// query_block.cost_info.query_cost (a decimal string); the row estimate
+// is taken from the plan tree by producedRows.
+func parseMySQLPlan(plan []byte) (*CostEstimate, error) {
+	var out struct {
+		QueryBlock map[string]any `json:"query_block"`
+	}
+	if err := json.Unmarshal(plan, &out); err != nil || out.QueryBlock == nil {
+		return nil, errs.Wrap(errs.ErrKindQueryFailed, "failed to parse query plan", err)
+	}
+
+	est := &CostEstimate{Rows: int64(producedRows(out.QueryBlock))}
+	if info, ok := out.QueryBlock["cost_info"].(map[string]any); ok {
+		if s, ok := info["query_cost"].(string); ok {
+			est.Cost, _ = strconv.ParseFloat(s, 64)
+		}
+	}
+	return est, nil
+}
+
+// mysqlPlanWrappers are the MySQL plan nodes that wrap the node producing
+// the query's rows, in the order they are tried.
+var mysqlPlanWrappers = []string{
+	"ordering_operation", "grouping_operation", "duplicates_removal",
+	"windowing", "union_result", "query_block", "table",
+}
+
+// producedRows estimates the rows a MySQL plan node outputs: a table's
+// rows_produced_per_join, the last table of a nested loop (join estimates
+// are cumulative), or the sum over UNION branches. Wrapper nodes are
+// descended; subqueries attached to a node are ignored.
+func producedRows(node map[string]any) float64 {
+	if r, ok := node["rows_produced_per_join"].(float64); ok {
+		return r
+	}
+	if loop, ok := node["nested_loop"].([]any); ok && len(loop) > 0 {
+		if last, ok := loop[len(loop)-1].(map[string]any); ok {
+			return producedRows(last)
+		}
+	}
+	if specs, ok := node["query_specifications"].([]any); ok {
+		var sum float64
+		for _, spec := range specs {
+			if m, ok := spec.(map[string]any); ok {
+				sum += producedRows(m)
+			}
+		}
+		return sum
+	}
+	for _, key := range mysqlPlanWrappers {
+		if child, ok := node[key].(map[string]any); ok {
+			return producedRows(child)
+		}
+	}
+	return 0
+}
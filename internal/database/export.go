@@ -0,0 +1,198 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// WriteCSV streams rows to w as CSV: a header row of column names, then
+// one record per row. Rows are written as they are read, so memory use
+// does not grow with the result set.
+//
+// Values are normalised as with ScanOptions.Typed, then formatted: NULL as
+// an empty field, time.Time as RFC 3339, []byte as text and decimals
+// exactly as the database returned them. WriteCSV always closes the Rows.
+func WriteCSV(w io.Writer, rows Rows) error {
+	cw := csv.NewWriter(w)
+	var record []string
+
+	writeErr := func(err error) error {
+		return errs.Wrap(errs.ErrKindUnknown, "failed to write CSV", err)
+	}
+
+	err := streamTyped(rows,
+		func(cols []string, _ []ColumnType) error {
+			record = make([]string, len(cols))
+			if err := cw.Write(cols); err != nil {
+				return writeErr(err)
+			}
+			return nil
+		},
+		func(vals []any) error {
+			for i, v := range vals {
+				record[i] = formatCSV(v)
+			}
+			if err := cw.Write(record); err != nil {
+				return writeErr(err)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return writeErr(err)
+	}
+	return nil
+}
+
+// WriteJSONL streams rows to w as JSON Lines: one JSON object per row,
+// keys in column order, each followed by a newline. Rows are written as
+// they are read, so memory use does not grow with the result set.
+//
+// Values are normalised as with ScanOptions.Typed, so MySQL's []byte
+// columns come out as strings and numbers rather than base64, decimals as
+// strings, and times as RFC 3339 strings. json/jsonb columns are embedded
+// as JSON rather than quoted. WriteJSONL always closes the Rows.
+func WriteJSONL(w io.Writer, rows Rows) error {
+	bw := bufio.NewWriter(w)
+	var names []string
+	var keys [][]byte // names, pre-encoded
+	var jsonCols []bool
+
+	writeErr := func(err error) error {
+		return errs.Wrap(errs.ErrKindUnknown, "failed to write JSON lines", err)
+	}
+
+	err := streamTyped(rows,
+		func(cols []string, types []ColumnType) error {
+			names = cols
+			keys = make([][]byte, len(cols))
+			jsonCols = make([]bool, len(cols))
+			for i, c := range cols {
+				keys[i], _ = json.Marshal(c) // strings always encode
+				jsonCols[i] = isJSONType(types[i].DatabaseType)
+			}
+			return nil
+		},
+		func(vals []any) error {
+			bw.WriteByte('{')
+			for i, v := range vals {
+				if i > 0 {
+					bw.WriteByte(',')
+				}
+				bw.Write(keys[i])
+				bw.WriteByte(':')
+
+				b, err := encodeJSONLValue(v, jsonCols[i])
+				if err != nil {
+					return wrapError(fmt.Sprintf("failed to encode column %q", names[i]), err)
+				}
+				bw.Write(b)
+			}
+			bw.WriteByte('}')
+			if err := bw.WriteByte('\n'); err != nil {
+				return writeErr(err)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return writeErr(err)
+	}
+	return nil
+}
+
+// encodeJSONLValue encodes one normalised value. Raw JSON text from
+// json/jsonb columns, which MySQL returns as []byte, is embedded compacted,
+// so it cannot break the line; other bytes are encoded as text. pgx
+// decodes json/jsonb itself, so those values (a JSON string included) are
+// marshalled like any other.
+func encodeJSONLValue(v any, isJSON bool) ([]byte, error) {
+	if b, ok := v.([]byte); ok {
+		if isJSON {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, b); err == nil {
+				return buf.Bytes(), nil
+			}
+		}
+		v = string(b)
+	}
+	return json.Marshal(v)
+}
+
+// formatCSV renders a normalised value as a CSV field.
+func formatCSV(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// streamTyped reads rows one at a time, normalising each value as
+// ScanOptions.Typed does, and hands them to onRow. onHeader is called once
+// with the columns before the first row, even for an empty result.
+// The vals slice is reused between calls. streamTyped always closes the
+// Rows, and returns errors from the callbacks unchanged.
+func streamTyped(rows Rows, onHeader func(cols []string, types []ColumnType) error, onRow func(vals []any) error) error {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return wrapError("failed to read column names", err)
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return wrapError("failed to read column types", err)
+	}
+
+	if err := onHeader(columns, types); err != nil {
+		return err
+	}
+
+	dest := make([]any, len(columns))
+	destPtrs := make([]any, len(columns))
+	for i := range dest {
+		destPtrs[i] = &dest[i]
+	}
+	vals := make([]any, len(columns))
+
+	for rows.Next() {
+		if err := rows.Scan(destPtrs...); err != nil {
+			return wrapError("failed to scan row", err)
+		}
+		for i, v := range dest {
+			if vals[i], err = normalizeValue(v, types[i].DatabaseType); err != nil {
+				return wrapError(fmt.Sprintf("failed to convert column %q", columns[i]), err)
+			}
+		}
+		if err := onRow(vals); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return wrapError("error during row iteration", err)
+	}
+	return nil
+}
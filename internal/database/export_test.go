@@ -0,0 +1,41 @@
+package database_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/database/dbtest"
+)
+
+func TestWriteJSONLJSONColumns(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbType string
+		value  any
+		want   string
+	}{
+		{"jsonb string of digits", "JSONB", "123", `{"doc":"123"}`},
+		{"jsonb string true", "JSONB", "true", `{"doc":"true"}`},
+		{"jsonb string null", "JSONB", "null", `{"doc":"null"}`},
+		{"jsonb number", "JSONB", float64(123), `{"doc":123}`},
+		{"jsonb object", "JSONB", map[string]any{"a": float64(1)}, `{"doc":{"a":1}}`},
+		{"mysql json raw", "JSON", []byte("{\n  \"a\": 1\n}"), `{"doc":{"a":1}}`},
+		{"mysql json raw string", "JSON", []byte(`"123"`), `{"doc":"123"}`},
+		{"mysql json invalid", "JSON", []byte(`not json`), `{"doc":"not json"}`},
+		{"text that looks like json", "TEXT", "123", `{"doc":"123"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := dbtest.NewRows([]string{"doc"}, []any{tt.value}).WithColumnTypes(tt.dbType)
+			var buf bytes.Buffer
+			if err := database.WriteJSONL(&buf, rows); err != nil {
+				t.Fatalf("WriteJSONL: %v", err)
+			}
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Errorf("WriteJSONL = %q, want %q", got, tt.want+"\n")
+			}
+		})
+	}
+}
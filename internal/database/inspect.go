@@ -0,0 +1,53 @@
+package database
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// InspectOptions narrows which tables InspectSchemaWith introspects, so
+// large databases only pay for the tables a caller actually serves.
+//
+// Patterns use path.Match glob syntax ("temp_*", "audit_20??", "[a-m]*")
+// and are matched against unqualified table names, case-sensitively.
+type InspectOptions struct {
+	// IncludeTables, when non-empty, limits introspection to tables
+	// matching at least one pattern.
+	IncludeTables []string
+
+	// ExcludeTables skips tables matching any pattern. Exclusion wins over
+	// inclusion.
+	ExcludeTables []string
+}
+
+// Validate reports a malformed pattern as ErrKindInvalidInput.
+func (o InspectOptions) Validate() error {
+	for _, patterns := range [][]string{o.IncludeTables, o.ExcludeTables} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return errs.Wrap(errs.ErrKindInvalidInput, fmt.Sprintf("invalid table pattern %q", p), err)
+			}
+		}
+	}
+	return nil
+}
+
+// Matches reports whether table passes the include and exclude filters.
+// Malformed patterns never match; call Validate to detect them.
+func (o InspectOptions) Matches(table string) bool {
+	if len(o.IncludeTables) > 0 && !matchAny(o.IncludeTables, table) {
+		return false
+	}
+	return !matchAny(o.ExcludeTables, table)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,11 @@ type DB interface {
 	// Ping verifies the database is reachable.
 	Ping(ctx context.Context) error
 
+	// Probe runs the configured probe query (Config.ProbeQuery) to verify
+	// the database can execute statements, not only accept connections —
+	// a deeper readiness check than Ping.
+	Probe(ctx context.Context) error
+
 	// Close releases all resources held by the connection pool.
 	Close()
 
@@ -18,6 +23,10 @@ type DB interface {
 	// QueryRow executes a SQL statement that returns at most one row.
 	QueryRow(ctx context.Context, sql string, args ...any) (Row, error)
 
+	// ExecResult executes a SQL statement that returns no rows, such as an
+	// INSERT, UPDATE or DELETE, and reports its effect.
+	ExecResult(ctx context.Context, sql string, args ...any) (*Result, error)
+
 	// ListTables returns all user-defined table names in the public schema.
 	ListTables(ctx context.Context) ([]string, error)
 
@@ -27,6 +36,58 @@ type DB interface {
 	// InspectSchema returns the full schema of the database.
 	// This is an expensive operation — callers should cache the result.
 	InspectSchema(ctx context.Context) (*Schema, error)
+
+	// InspectSchemaWith is InspectSchema restricted to the tables selected
+	// by opts. The zero InspectOptions behaves like InspectSchema.
+	InspectSchemaWith(ctx context.Context, opts InspectOptions) (*Schema, error)
+
+	// InspectTable returns the schema of a single table, without the cost
+	// of introspecting the whole database. It returns ErrKindNotFound when
+	// the table does not exist.
+	InspectTable(ctx context.Context, table string) (*TableInfo, error)
+
+	// Begin starts a transaction with the driver's default isolation level.
+	Begin(ctx context.Context) (Tx, error)
+
+	// BeginTx starts a transaction with the given options. The zero
+	// TxOptions behaves like Begin.
+	BeginTx(ctx context.Context, opts TxOptions) (Tx, error)
+
+	// Dialect reports the SQL dialect the driver speaks, for building
+	// queries and engine-specific statements against it.
+	Dialect() Dialect
+}
+
+// Tx is an in-progress database transaction.
+// Callers must finish it with Commit or Rollback. Rollback after Commit is
+// a no-op, so `defer tx.Rollback(ctx)` is always safe.
+type Tx interface {
+	// Query executes a SQL statement that returns multiple rows.
+	Query(ctx context.Context, sql string, args ...any) (Rows, error)
+
+	// QueryRow executes a SQL statement that returns at most one row.
+	QueryRow(ctx context.Context, sql string, args ...any) (Row, error)
+
+	// ExecResult executes a SQL statement that returns no rows and
+	// reports its effect.
+	ExecResult(ctx context.Context, sql string, args ...any) (*Result, error)
+
+	// Commit makes the transaction's changes permanent.
+	Commit(ctx context.Context) error
+
+	// Rollback discards the transaction's changes.
+	Rollback(ctx context.Context) error
+
+	// Savepoint marks a named point that RollbackTo can return to.
+	// name must be a plain identifier (see ValidateSavepointName).
+	Savepoint(ctx context.Context, name string) error
+
+	// RollbackTo undoes everything done since the named savepoint, which
+	// remains defined and can be rolled back to again.
+	RollbackTo(ctx context.Context, name string) error
+
+	// ReleaseSavepoint forgets the named savepoint, keeping its changes.
+	ReleaseSavepoint(ctx context.Context, name string) error
 }
 
 // Rows is an abstraction over a database result set.
@@ -42,6 +103,10 @@ type Rows interface {
 	// Columns returns the column names of the result set.
 	Columns() ([]string, error)
 
+	// ColumnTypes returns the name and database type of each column,
+	// in result-set order.
+	ColumnTypes() ([]ColumnType, error)
+
 	// Close releases resources held by the result set.
 	Close()
 
@@ -49,6 +114,37 @@ type Rows interface {
 	Err() error
 }
 
+// ColumnType describes a single column of a result set.
+type ColumnType struct {
+	// Name is the column name as returned by the query.
+	Name string
+
+	// DatabaseType is the engine's upper-case type name, e.g. "INT4",
+	// "TIMESTAMPTZ", "_TEXT" (Postgres arrays) or "VARCHAR", "DECIMAL"
+	// (MySQL). Empty if the driver cannot name the type.
+	DatabaseType string
+}
+
+// Result reports the effect of a statement run with ExecResult.
+type Result struct {
+	// RowsAffected is the number of rows inserted, updated or deleted.
+	// On MySQL an UPDATE counts only rows whose values actually changed.
+	RowsAffected int64
+
+	// LastInsertID is the AUTO_INCREMENT value generated by a MySQL
+	// INSERT — for a multi-row INSERT, the value of the first row. It is
+	// always zero on Postgres, which has no equivalent; use
+	// `INSERT … RETURNING id` with QueryRow there.
+	LastInsertID int64
+}
+
+// SessionConn is a single pooled connection, as passed to
+// Config.AfterConnect. Statements run on it affect only that session.
+type SessionConn interface {
+	// Exec executes a statement that returns no rows.
+	Exec(ctx context.Context, sql string, args ...any) error
+}
+
 // Row is an abstraction over a single database row.
 type Row interface {
 	Scan(dest ...any) error
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// ScanJSON unmarshals a json/jsonb column value into dest, which must be a
+// pointer (e.g. *map[string]any or a pointer to a struct).
+//
+// raw may be the []byte or string MySQL returns, or a value pgx has already
+// decoded; the latter is re-encoded so dest's type is always honoured.
+// A NULL column (raw == nil) leaves dest untouched.
+func ScanJSON(raw any, dest any) error {
+	var data []byte
+	switch v := raw.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return errs.Wrap(errs.ErrKindQueryFailed, fmt.Sprintf("cannot re-encode %T as JSON", raw), err)
+		}
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return errs.Wrap(errs.ErrKindQueryFailed, "invalid JSON column value", err)
+	}
+	return nil
+}
+
+// isJSONType reports whether a ColumnType.DatabaseType holds JSON.
+func isJSONType(dbType string) bool {
+	return dbType == "JSON" || dbType == "JSONB"
+}
@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// killTimeout bounds how long a KILL QUERY issued on cancellation may take.
+const killTimeout = 5 * time.Second
+
+var (
+	// markerPrefix keeps markers from different processes sharing a server
+	// apart. rand.Text uses only A-Z and 2-7, so it is safe inside LIKE.
+	markerPrefix = rand.Text()
+	markerSeq    atomic.Uint64
+)
+
+// killOnCancel marks query with a unique comment and arranges for the
+// statement carrying that marker to be stopped with KILL QUERY if ctx is
+// cancelled before stop is called. It returns the marked query. Every path
+// that sends caller SQL goes through it, since closing the client socket
+// alone leaves MySQL executing the statement.
+//
+// Until ctx is cancelled this costs nothing but the comment: the statement
+// is found by its marker in the process list only once the kill fires,
+// rather than by asking each connection for CONNECTION_ID() up front.
+// Contexts that can never be cancelled are left unmarked. stop never
+// blocks.
+func (d *Driver) killOnCancel(ctx context.Context, query string) (marked string, stop func()) {
+	if ctx.Done() == nil {
+		return query, func() {}
+	}
+	marker := "/* datri:" + markerPrefix + "-" + strconv.FormatUint(markerSeq.Add(1), 10) + " */"
+	cancelStop := context.AfterFunc(ctx, func() { d.killMarked(marker) })
+	return marker + " " + query, func() { cancelStop() }
+}
+
+// killMarked issues KILL QUERY for the statement whose text contains
+// marker, if it is still running. Both statements run on a connection from
+// d.killer, so a cancelled query never waits for a free pooled connection
+// to be stopped.
+//
+// A connection cancelled mid-statement is discarded by the driver, so its
+// thread is not reused. Only a statement that completes between the lookup
+// and the KILL leaves its connection in the pool, and that gap is a single
+// round trip on the killer connection.
+func (d *Driver) killMarked(marker string) {
+	ctx, cancel := context.WithTimeout(context.Background(), killTimeout)
+	defer cancel()
+
+	conn, err := d.killer.Conn(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	const q = `
+		SELECT id
+		FROM information_schema.processlist
+		WHERE info LIKE ?
+		  AND id <> CONNECTION_ID()`
+
+	var id uint64
+	// No row means the statement finished, or never reached the server.
+	if err := conn.QueryRowContext(ctx, q, "%"+marker+"%").Scan(&id); err != nil {
+		return
+	}
+	// id is a server-issued integer, safe to format into the statement.
+	_, _ = conn.ExecContext(ctx, fmt.Sprintf("KILL QUERY %d", id))
+}
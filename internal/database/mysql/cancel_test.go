@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// openTestDriver connects to the server named by DATRI_TEST_MYSQL_DSN,
+// skipping the test when it is unset.
+func openTestDriver(t *testing.T) *Driver {
+	t.Helper()
+	dsn := os.Getenv("DATRI_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("DATRI_TEST_MYSQL_DSN not set")
+	}
+	cfg := database.DefaultConfig(dsn)
+	cfg.Driver = database.DriverMySQL
+	cfg.MinConns = 0
+	d, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return d
+}
+
+// drain reads rows to the end and returns the first error met.
+func drain(rows database.Rows, err error) error {
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// eventually polls cond until it holds or the deadline passes.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// checkGoroutines fails the test if the goroutine count does not settle
+// back to base.
+func checkGoroutines(t *testing.T, base int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= base {
+			return
+		}
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			buf = buf[:runtime.Stack(buf, true)]
+			t.Fatalf("goroutines leaked: %d running, want at most %d\n%s", n, base, buf)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestKillOnCancelMarksQuery(t *testing.T) {
+	d := &Driver{}
+	if q, stop := d.killOnCancel(context.Background(), "SELECT 1"); q != "SELECT 1" {
+		stop()
+		t.Errorf("query under an uncancellable context = %q, want it unmarked", q)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q1, stop1 := d.killOnCancel(ctx, "SELECT 1")
+	q2, stop2 := d.killOnCancel(ctx, "SELECT 1")
+	stop1()
+	stop2()
+
+	if q1 == q2 {
+		t.Fatalf("markers repeat: %q", q1)
+	}
+	for _, q := range []string{q1, q2} {
+		if !strings.HasPrefix(q, "/* datri:") || !strings.HasSuffix(q, " */ SELECT 1") {
+			t.Errorf("marked query = %q, want /* datri:... */ SELECT 1", q)
+		}
+		if strings.ContainsAny(q, "%_\\") {
+			t.Errorf("marked query %q holds a LIKE metacharacter", q)
+		}
+	}
+}
+
+func TestQueryCancelKillsStatement(t *testing.T) {
+	testCancelKillsStatement(t, "SELECT SLEEP(30) AS query_cancel_test", func(ctx context.Context, d *Driver, stmt string) error {
+		return drain(d.Query(ctx, stmt))
+	})
+}
+
+func TestQueryRowCancelKillsStatement(t *testing.T) {
+	testCancelKillsStatement(t, "SELECT SLEEP(30) AS query_row_cancel_test", func(ctx context.Context, d *Driver, stmt string) error {
+		row, err := d.QueryRow(ctx, stmt)
+		if err != nil {
+			return err
+		}
+		var slept int
+		return row.Scan(&slept)
+	})
+}
+
+func TestExecResultCancelKillsStatement(t *testing.T) {
+	testCancelKillsStatement(t, "DO SLEEP(30) /* exec_cancel_test */", func(ctx context.Context, d *Driver, stmt string) error {
+		_, err := d.ExecResult(ctx, stmt)
+		return err
+	})
+}
+
+func TestTxExecResultCancelKillsStatement(t *testing.T) {
+	testCancelKillsStatement(t, "DO SLEEP(30) /* tx_exec_cancel_test */", func(ctx context.Context, d *Driver, stmt string) error {
+		tx, err := d.Begin(context.Background())
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(context.Background())
+		_, err = tx.ExecResult(ctx, stmt)
+		return err
+	})
+}
+
+// testCancelKillsStatement runs stmt through run under a context that
+// times out after 200ms, then checks that the call failed with
+// ErrKindTimeout, the statement is gone from the server and nothing leaked.
+func testCancelKillsStatement(t *testing.T, stmt string, run func(ctx context.Context, d *Driver, stmt string) error) {
+	t.Helper()
+	base := runtime.NumGoroutine()
+	d := openTestDriver(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := run(ctx, d, stmt)
+	if got := errs.KindOf(err); got != errs.ErrKindTimeout {
+		t.Fatalf("error kind = %v (%v), want %v", got, err, errs.ErrKindTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled call returned after %s", elapsed)
+	}
+
+	eventually(t, "the statement to be killed", func() bool {
+		var n int
+		err := d.db.QueryRow(`
+			SELECT COUNT(*)
+			FROM information_schema.processlist
+			WHERE info LIKE ?
+			  AND id <> CONNECTION_ID()`, "%"+stmt+"%").Scan(&n)
+		return err == nil && n == 0
+	})
+	eventually(t, "the pool to drain", func() bool { return d.db.Stats().InUse == 0 })
+
+	d.Close()
+	checkGoroutines(t, base)
+}
+
+func TestQueryCancellableContextDoesNotLeak(t *testing.T) {
+	base := runtime.NumGoroutine()
+	d := openTestDriver(t)
+
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := drain(d.Query(ctx, "SELECT 1"))
+		cancel()
+		if err != nil {
+			t.Fatalf("Query %d: %v", i, err)
+		}
+	}
+	if n := d.db.Stats().InUse; n != 0 {
+		t.Errorf("%d connections still in use", n)
+	}
+
+	d.Close()
+	checkGoroutines(t, base)
+}
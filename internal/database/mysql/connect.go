@@ -0,0 +1,61 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/koustreak/DatRi/internal/database"
+)
+
+// hookConnector runs Config.AfterConnect on every new connection before
+// database/sql hands it out.
+type hookConnector struct {
+	driver.Connector
+	hook func(context.Context, database.SessionConn) error
+}
+
+func (c *hookConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.hook(ctx, sessionConn{conn}); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// sessionConn exposes a raw driver connection as a database.SessionConn.
+type sessionConn struct {
+	conn driver.Conn
+}
+
+func (c sessionConn) Exec(ctx context.Context, query string, args ...any) error {
+	named := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: a}
+	}
+
+	// The driver executes statements with args as prepared statements
+	// unless interpolateParams is set, signalling that with ErrSkip.
+	_, err := c.conn.(driver.ExecerContext).ExecContext(ctx, query, named)
+	if errors.Is(err, driver.ErrSkip) {
+		err = c.prepareExec(ctx, query, named)
+	}
+	if err != nil {
+		return mapQueryError(ctx, err, "session statement failed")
+	}
+	return nil
+}
+
+func (c sessionConn) prepareExec(ctx context.Context, query string, args []driver.NamedValue) error {
+	stmt, err := c.conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+	return err
+}
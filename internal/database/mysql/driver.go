@@ -3,8 +3,11 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/koustreak/DatRi/internal/database"
@@ -16,29 +19,71 @@ import (
 // Driver is a MySQL implementation of database.DB backed by database/sql.
 // It is safe for concurrent use by multiple goroutines.
 type Driver struct {
-	db *sql.DB
+	db             *sql.DB
+	killer         *sql.DB       // unpooled connections for KILL QUERY; see killOnCancel
+	acquireTimeout time.Duration // zero: Query waits on the pool as long as ctx allows
+	probeQuery     string
+}
+
+func init() {
+	database.Register(database.DriverMySQL, func(ctx context.Context, cfg *database.Config) (database.DB, error) {
+		d, err := New(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	})
 }
 
 // New opens a MySQL connection pool using the provided Config and returns a Driver.
 // It calls Ping to validate the connection before returning.
 func New(ctx context.Context, cfg *database.Config) (*Driver, error) {
-	db, err := sql.Open("mysql", cfg.DSN)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	dsn, err := cfg.BuildDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlCfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrKindConnectionFailed, "invalid DSN", err)
+	}
+	connector, err := mysql.NewConnector(mysqlCfg)
 	if err != nil {
 		return nil, errs.Wrap(errs.ErrKindConnectionFailed, "invalid DSN", err)
 	}
+	// KILL QUERY must not wait behind the queries it is meant to stop, so it
+	// runs outside the main pool on a connection dialled for the purpose.
+	killer := sql.OpenDB(connector)
+	killer.SetMaxIdleConns(0)
+
+	if cfg.AfterConnect != nil {
+		connector = &hookConnector{Connector: connector, hook: cfg.AfterConnect}
+	}
+	db := sql.OpenDB(connector)
 
 	db.SetMaxOpenConns(int(cfg.MaxConns))
 	db.SetMaxIdleConns(int(cfg.MinConns))
 	db.SetConnMaxLifetime(cfg.MaxConnLifetime)
 	db.SetConnMaxIdleTime(cfg.MaxConnIdleTime)
 
-	d := &Driver{db: db}
+	d := &Driver{db: db, killer: killer, acquireTimeout: cfg.AcquireTimeout, probeQuery: cfg.ProbeQuery}
+	if d.probeQuery == "" {
+		d.probeQuery = database.DefaultProbeQuery
+	}
 
-	pingCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	// A zero ConnectTimeout means "no limit", matching the Postgres driver.
+	pingCtx, cancel := ctx, context.CancelFunc(func() {})
+	if cfg.ConnectTimeout > 0 {
+		pingCtx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+	}
 	defer cancel()
 
 	if err := d.Ping(pingCtx); err != nil {
-		_ = db.Close()
+		d.Close()
 		return nil, err
 	}
 
@@ -54,21 +99,157 @@ func (d *Driver) Ping(ctx context.Context) error {
 	return nil
 }
 
+// Probe runs the probe query to completion, discarding its rows.
+func (d *Driver) Probe(ctx context.Context) error {
+	rows, err := d.Query(ctx, d.probeQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
 func (d *Driver) Close() {
 	_ = d.db.Close()
+	_ = d.killer.Close()
+}
+
+// Dialect returns database.DialectMySQL.
+func (d *Driver) Dialect() database.Dialect {
+	return database.DialectMySQL
 }
 
-func (d *Driver) Query(ctx context.Context, sql string, args ...any) (database.Rows, error) {
-	rows, err := d.db.QueryContext(ctx, sql, args...)
+// Query executes a SQL statement that returns multiple rows.
+//
+// Cancelling ctx while the statement runs aborts it server-side with
+// KILL QUERY — closing the client socket alone leaves MySQL executing it.
+// Nothing extra runs unless ctx is actually cancelled; see killOnCancel.
+func (d *Driver) Query(ctx context.Context, query string, args ...any) (database.Rows, error) {
+	query = database.TagQuery(ctx, query)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	query, stop := d.killOnCancel(ctx, query)
+	if d.acquireTimeout == 0 {
+		rows, err := d.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			stop()
+			return nil, mapQueryError(ctx, err, "query failed")
+		}
+		return &mysqlRows{rows: rows, ctx: ctx, release: stop}, nil
+	}
+
+	conn, err := d.conn(ctx)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	rows, err := conn.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, mapError(err, "query failed")
+		stop()
+		_ = conn.Close()
+		return nil, mapQueryError(ctx, err, "query failed")
 	}
-	return &mysqlRows{rows: rows}, nil
+	return &mysqlRows{rows: rows, ctx: ctx, release: func() {
+		stop()
+		_ = conn.Close()
+	}}, nil
 }
 
+// QueryRow executes a SQL statement expected to return at most one row.
 func (d *Driver) QueryRow(ctx context.Context, query string, args ...any) (database.Row, error) {
-	row := d.db.QueryRowContext(ctx, query, args...)
-	return &mysqlRow{row: row}, nil
+	query = database.TagQuery(ctx, query)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	query, stop := d.killOnCancel(ctx, query)
+	if d.acquireTimeout == 0 {
+		return &mysqlRow{row: d.db.QueryRowContext(ctx, query, args...), ctx: ctx, release: stop}, nil
+	}
+
+	conn, err := d.conn(ctx)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	row := conn.QueryRowContext(ctx, query, args...)
+	return &mysqlRow{row: row, ctx: ctx, release: func() {
+		stop()
+		_ = conn.Close()
+	}}, nil
+}
+
+// ExecResult executes a statement that returns no rows, reporting the
+// affected row count and the AUTO_INCREMENT value of an INSERT. As with
+// Query, cancelling ctx stops the statement server-side.
+func (d *Driver) ExecResult(ctx context.Context, query string, args ...any) (*database.Result, error) {
+	query = database.TagQuery(ctx, query)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	query, stop := d.killOnCancel(ctx, query)
+	defer stop()
+	if d.acquireTimeout == 0 {
+		res, err := d.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, mapQueryError(ctx, err, "exec failed")
+		}
+		return toResult(res)
+	}
+
+	conn, err := d.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	res, err := conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapQueryError(ctx, err, "exec failed")
+	}
+	return toResult(res)
+}
+
+// toResult converts a sql.Result. go-sql-driver/mysql never fails either
+// call, but the interface allows it.
+func toResult(res sql.Result) (*database.Result, error) {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, mapError(err, "failed to read rows affected")
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, mapError(err, "failed to read last insert id")
+	}
+	return &database.Result{RowsAffected: affected, LastInsertID: id}, nil
+}
+
+// conn takes a dedicated connection from the pool, waiting at most
+// acquireTimeout when one is set. The timeout covers only the wait: the
+// query itself runs under ctx.
+func (d *Driver) conn(ctx context.Context) (*sql.Conn, error) {
+	if d.acquireTimeout == 0 {
+		conn, err := d.db.Conn(ctx)
+		if err != nil {
+			return nil, mapQueryError(ctx, err, "failed to acquire connection")
+		}
+		return conn, nil
+	}
+
+	acquireCtx, cancel := context.WithTimeout(ctx, d.acquireTimeout)
+	defer cancel()
+
+	conn, err := d.db.Conn(acquireCtx)
+	if err != nil {
+		if ctx.Err() == nil && acquireCtx.Err() != nil {
+			return nil, errs.Wrap(errs.ErrKindTimeout,
+				fmt.Sprintf("no pooled connection available within %s", d.acquireTimeout), err)
+		}
+		return nil, mapQueryError(ctx, err, "failed to acquire connection")
+	}
+	return conn, nil
 }
 
 func (d *Driver) ListTables(ctx context.Context) ([]string, error) {
@@ -119,6 +300,15 @@ func (d *Driver) TableExists(ctx context.Context, table string) (bool, error) {
 }
 
 func (d *Driver) InspectSchema(ctx context.Context) (*database.Schema, error) {
+	return d.InspectSchemaWith(ctx, database.InspectOptions{})
+}
+
+// InspectSchemaWith is InspectSchema limited to the tables opts selects.
+func (d *Driver) InspectSchemaWith(ctx context.Context, opts database.InspectOptions) (*database.Schema, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	tables, err := d.ListTables(ctx)
 	if err != nil {
 		return nil, err
@@ -129,6 +319,9 @@ func (d *Driver) InspectSchema(ctx context.Context) (*database.Schema, error) {
 	}
 
 	for _, tableName := range tables {
+		if !opts.Matches(tableName) {
+			continue
+		}
 		info, err := d.inspectTable(ctx, tableName)
 		if err != nil {
 			return nil, fmt.Errorf("inspecting table %q: %w", tableName, err)
@@ -139,6 +332,19 @@ func (d *Driver) InspectSchema(ctx context.Context) (*database.Schema, error) {
 	return schema, nil
 }
 
+// InspectTable introspects a single table. It returns ErrKindNotFound when
+// the table does not exist.
+func (d *Driver) InspectTable(ctx context.Context, table string) (*database.TableInfo, error) {
+	exists, err := d.TableExists(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errs.New(errs.ErrKindNotFound, fmt.Sprintf("table %q does not exist", table))
+	}
+	return d.inspectTable(ctx, table)
+}
+
 func (d *Driver) inspectTable(ctx context.Context, table string) (*database.TableInfo, error) {
 	columns, pks, err := d.fetchColumns(ctx, table)
 	if err != nil {
@@ -150,11 +356,23 @@ func (d *Driver) inspectTable(ctx context.Context, table string) (*database.Tabl
 		return nil, err
 	}
 
+	comment, err := d.fetchTableComment(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := d.fetchIndexes(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
 	return &database.TableInfo{
 		Name:        table,
 		Columns:     columns,
 		PrimaryKey:  pks,
 		ForeignKeys: fks,
+		Indexes:     indexes,
+		Comment:     comment,
 	}, nil
 }
 
@@ -164,7 +382,12 @@ func (d *Driver) fetchColumns(ctx context.Context, table string) ([]*database.Co
 		       data_type,
 		       is_nullable = 'YES',
 		       column_default,
-		       column_key
+		       column_key,
+		       NULLIF(column_comment, ''),
+		       collation_name,
+		       character_set_name,
+		       numeric_precision,
+		       numeric_scale
 		FROM information_schema.columns
 		WHERE table_schema = DATABASE()
 		  AND table_name   = ?
@@ -182,7 +405,8 @@ func (d *Driver) fetchColumns(ctx context.Context, table string) ([]*database.Co
 	for rows.Next() {
 		var c database.ColumnInfo
 		var columnKey string
-		if err := rows.Scan(&c.Name, &c.DataType, &c.Nullable, &c.Default, &columnKey); err != nil {
+		if err := rows.Scan(&c.Name, &c.DataType, &c.Nullable, &c.Default, &columnKey, &c.Comment,
+			&c.Collation, &c.CharSet, &c.NumericPrecision, &c.NumericScale); err != nil {
 			return nil, nil, mapError(err, "failed to scan column info")
 		}
 		c.IsPrimary = columnKey == "PRI"
@@ -196,15 +420,69 @@ func (d *Driver) fetchColumns(ctx context.Context, table string) ([]*database.Co
 	return cols, pks, rows.Err()
 }
 
+func (d *Driver) fetchTableComment(ctx context.Context, table string) (*string, error) {
+	const q = `
+		SELECT NULLIF(table_comment, '')
+		FROM information_schema.tables
+		WHERE table_schema = DATABASE()
+		  AND table_name   = ?`
+
+	var comment *string
+	if err := d.db.QueryRowContext(ctx, q, table).Scan(&comment); err != nil {
+		return nil, mapError(err, "failed to fetch table comment")
+	}
+	return comment, nil
+}
+
+func (d *Driver) fetchIndexes(ctx context.Context, table string) ([]*database.Index, error) {
+	const q = `
+		SELECT index_name,
+		       non_unique = 0,
+		       column_name
+		FROM information_schema.statistics
+		WHERE table_schema = DATABASE()
+		  AND table_name   = ?
+		ORDER BY index_name, seq_in_index`
+
+	rows, err := d.db.QueryContext(ctx, q, table)
+	if err != nil {
+		return nil, mapError(err, "failed to fetch indexes")
+	}
+	defer rows.Close()
+
+	var indexes []*database.Index
+	for rows.Next() {
+		var name string
+		var unique bool
+		var column sql.NullString // NULL for functional key parts
+		if err := rows.Scan(&name, &unique, &column); err != nil {
+			return nil, mapError(err, "failed to scan index")
+		}
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, &database.Index{Name: name, Unique: unique})
+		}
+		idx := indexes[len(indexes)-1]
+		idx.Columns = append(idx.Columns, column.String) // "" for functional parts
+	}
+	return indexes, rows.Err()
+}
+
 func (d *Driver) fetchForeignKeys(ctx context.Context, table string) ([]*database.ForeignKey, error) {
 	const q = `
-		SELECT column_name,
-		       referenced_table_name,
-		       referenced_column_name
-		FROM information_schema.key_column_usage
-		WHERE table_schema              = DATABASE()
-		  AND table_name                = ?
-		  AND referenced_table_name    IS NOT NULL`
+		SELECT kcu.constraint_name,
+		       kcu.column_name,
+		       kcu.referenced_table_name,
+		       kcu.referenced_column_name,
+		       rc.delete_rule,
+		       rc.update_rule
+		FROM information_schema.key_column_usage kcu
+		JOIN information_schema.referential_constraints rc
+		  ON rc.constraint_schema = kcu.table_schema
+		 AND rc.constraint_name   = kcu.constraint_name
+		WHERE kcu.table_schema           = DATABASE()
+		  AND kcu.table_name             = ?
+		  AND kcu.referenced_table_name IS NOT NULL
+		ORDER BY kcu.constraint_name, kcu.ordinal_position`
 
 	rows, err := d.db.QueryContext(ctx, q, table)
 	if err != nil {
@@ -212,13 +490,25 @@ func (d *Driver) fetchForeignKeys(ctx context.Context, table string) ([]*databas
 	}
 	defer rows.Close()
 
+	// Rows arrive one per column; consecutive rows of the same constraint
+	// form one composite key.
 	var fks []*database.ForeignKey
 	for rows.Next() {
-		fk := &database.ForeignKey{}
-		if err := rows.Scan(&fk.Column, &fk.RefTable, &fk.RefColumn); err != nil {
+		var name, column, refTable, refColumn, onDelete, onUpdate string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
 			return nil, mapError(err, "failed to scan foreign key")
 		}
-		fks = append(fks, fk)
+		if len(fks) == 0 || fks[len(fks)-1].Name != name {
+			fks = append(fks, &database.ForeignKey{
+				Name:     name,
+				RefTable: refTable,
+				OnDelete: onDelete,
+				OnUpdate: onUpdate,
+			})
+		}
+		fk := fks[len(fks)-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
 	}
 	return fks, rows.Err()
 }
@@ -226,20 +516,67 @@ func (d *Driver) fetchForeignKeys(ctx context.Context, table string) ([]*databas
 // --- sql.DB type wrappers ---
 
 type mysqlRows struct {
-	rows *sql.Rows
+	rows    *sql.Rows
+	ctx     context.Context
+	release func() // stops the kill watch and returns a dedicated connection; may be nil
 }
 
 func (r *mysqlRows) Next() bool                 { return r.rows.Next() }
 func (r *mysqlRows) Scan(dest ...any) error     { return r.rows.Scan(dest...) }
 func (r *mysqlRows) Columns() ([]string, error) { return r.rows.Columns() }
-func (r *mysqlRows) Close()                     { _ = r.rows.Close() }
-func (r *mysqlRows) Err() error                 { return r.rows.Err() }
+
+func (r *mysqlRows) ColumnTypes() ([]database.ColumnType, error) {
+	cts, err := r.rows.ColumnTypes()
+	if err != nil {
+		return nil, mapError(err, "failed to read column types")
+	}
+	types := make([]database.ColumnType, len(cts))
+	for i, ct := range cts {
+		types[i] = database.ColumnType{Name: ct.Name(), DatabaseType: ct.DatabaseTypeName()}
+	}
+	return types, nil
+}
+
+func (r *mysqlRows) Close() {
+	_ = r.rows.Close()
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+}
+
+// Err maps iteration errors so a query cancelled mid-stream reports
+// ErrKindTimeout rather than a generic failure.
+func (r *mysqlRows) Err() error {
+	if err := r.rows.Err(); err != nil {
+		return mapQueryError(r.ctx, err, "error during row iteration")
+	}
+	return nil
+}
 
 type mysqlRow struct {
-	row *sql.Row
+	row     *sql.Row
+	ctx     context.Context
+	release func() // stops the kill watch and returns a dedicated connection; may be nil
 }
 
-func (r *mysqlRow) Scan(dest ...any) error { return r.row.Scan(dest...) }
+// Scan maps no-rows, server and cancellation errors; decode errors are
+// returned untouched for the caller to classify.
+func (r *mysqlRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+	if err == nil {
+		return nil
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.Is(err, sql.ErrNoRows) || errors.As(err, &mysqlErr) || r.ctx.Err() != nil {
+		return mapQueryError(r.ctx, err, "query failed")
+	}
+	return err
+}
 
 // --- error mapping ---
 
@@ -259,16 +596,34 @@ func mapError(err error, msg string) *errs.Error {
 
 	var mysqlErr *mysql.MySQLError
 	if errors.As(err, &mysqlErr) {
-		return errs.Wrap(
+		e := errs.Wrap(
 			classifyMySQLCode(mysqlErr.Number),
 			fmt.Sprintf("%s: %s", msg, mysqlErr.Message),
 			err,
 		)
+		e.Code = strconv.Itoa(int(mysqlErr.Number))
+		return e
 	}
 
 	return errs.Wrap(errs.ErrKindConnectionFailed, msg, err)
 }
 
+// mapQueryError is mapError for errors raised while a statement runs.
+// When ctx is already done the driver may report the aborted connection
+// (driver.ErrBadConn, mysql.ErrInvalidConn) instead of ctx.Err(), so the
+// context state takes precedence and the error maps to ErrKindTimeout.
+func mapQueryError(ctx context.Context, err error, msg string) *errs.Error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+			err = fmt.Errorf("%w: %w", ctxErr, err)
+		}
+		if errors.Is(err, ctxErr) {
+			return errs.Wrap(errs.ErrKindTimeout, msg, err)
+		}
+	}
+	return mapError(err, msg)
+}
+
 // classifyMySQLCode maps MySQL error numbers to ErrKind.
 func classifyMySQLCode(code uint16) errs.ErrKind {
 	switch code {
@@ -278,6 +633,10 @@ func classifyMySQLCode(code uint16) errs.ErrKind {
 		return errs.ErrKindConnectionFailed
 	case 1054, 1064, 1146:
 		return errs.ErrKindQueryFailed
+	case 1048, 1062, 1216, 1217, 1451, 1452, 3819: // NOT NULL, duplicate key, FK, CHECK
+		return errs.ErrKindConflict
+	case 1264, 1406: // out of range, data too long
+		return errs.ErrKindInvalidInput
 	default:
 		return errs.ErrKindQueryFailed
 	}
@@ -0,0 +1,137 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/koustreak/DatRi/internal/database"
+)
+
+// Begin starts a transaction with the server's default isolation level.
+func (d *Driver) Begin(ctx context.Context) (database.Tx, error) {
+	return d.BeginTx(ctx, database.TxOptions{})
+}
+
+// BeginTx starts a transaction with the given isolation level and access mode.
+func (d *Driver) BeginTx(ctx context.Context, opts database.TxOptions) (database.Tx, error) {
+	txOpts := &sql.TxOptions{ReadOnly: opts.ReadOnly}
+	switch opts.IsolationLevel {
+	case database.IsolationReadCommitted:
+		txOpts.Isolation = sql.LevelReadCommitted
+	case database.IsolationRepeatableRead:
+		txOpts.Isolation = sql.LevelRepeatableRead
+	case database.IsolationSerializable:
+		txOpts.Isolation = sql.LevelSerializable
+	}
+
+	if d.acquireTimeout == 0 {
+		tx, err := d.db.BeginTx(ctx, txOpts)
+		if err != nil {
+			return nil, mapQueryError(ctx, err, "failed to begin transaction")
+		}
+		return &mysqlTx{d: d, tx: tx}, nil
+	}
+
+	conn, err := d.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTx(ctx, txOpts)
+	if err != nil {
+		_ = conn.Close()
+		return nil, mapQueryError(ctx, err, "failed to begin transaction")
+	}
+	return &mysqlTx{d: d, tx: tx, release: func() { _ = conn.Close() }}, nil
+}
+
+// mysqlTx implements database.Tx on top of *sql.Tx.
+type mysqlTx struct {
+	d       *Driver // for killOnCancel
+	tx      *sql.Tx
+	release func() // returns an explicitly acquired connection to the pool; may be nil
+}
+
+// done releases an explicitly acquired connection once the transaction has
+// ended. Later calls are no-ops, so a deferred Rollback after Commit is safe.
+func (t *mysqlTx) done() {
+	if t.release != nil {
+		t.release()
+		t.release = nil
+	}
+}
+
+func (t *mysqlTx) Query(ctx context.Context, query string, args ...any) (database.Rows, error) {
+	query = database.TagQuery(ctx, query)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	query, stop := t.d.killOnCancel(ctx, query)
+	rows, err := t.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		stop()
+		return nil, mapQueryError(ctx, err, "query failed")
+	}
+	return &mysqlRows{rows: rows, ctx: ctx, release: stop}, nil
+}
+
+func (t *mysqlTx) QueryRow(ctx context.Context, query string, args ...any) (database.Row, error) {
+	query = database.TagQuery(ctx, query)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	query, stop := t.d.killOnCancel(ctx, query)
+	return &mysqlRow{row: t.tx.QueryRowContext(ctx, query, args...), ctx: ctx, release: stop}, nil
+}
+
+func (t *mysqlTx) ExecResult(ctx context.Context, query string, args ...any) (*database.Result, error) {
+	query = database.TagQuery(ctx, query)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	query, stop := t.d.killOnCancel(ctx, query)
+	defer stop()
+	res, err := t.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapQueryError(ctx, err, "exec failed")
+	}
+	return toResult(res)
+}
+
+func (t *mysqlTx) Commit(ctx context.Context) error {
+	defer t.done()
+	if err := t.tx.Commit(); err != nil {
+		return mapQueryError(ctx, err, "commit failed")
+	}
+	return nil
+}
+
+func (t *mysqlTx) Rollback(ctx context.Context) error {
+	defer t.done()
+	if err := t.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return mapQueryError(ctx, err, "rollback failed")
+	}
+	return nil
+}
+
+func (t *mysqlTx) Savepoint(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "SAVEPOINT ", name, "failed to create savepoint")
+}
+
+func (t *mysqlTx) RollbackTo(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "ROLLBACK TO SAVEPOINT ", name, "failed to roll back to savepoint")
+}
+
+func (t *mysqlTx) ReleaseSavepoint(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "RELEASE SAVEPOINT ", name, "failed to release savepoint")
+}
+
+func (t *mysqlTx) execSavepoint(ctx context.Context, stmt, name, errMsg string) error {
+	if err := database.ValidateSavepointName(name); err != nil {
+		return err
+	}
+	if _, err := t.tx.ExecContext(ctx, stmt+name); err != nil {
+		return mapQueryError(ctx, err, errMsg)
+	}
+	return nil
+}
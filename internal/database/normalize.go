@@ -0,0 +1,262 @@
+package database
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// typeCategory groups engine-specific type names into the Go type a value
+// is normalised to by ScanOptions.Typed.
+type typeCategory int
+
+const (
+	categoryOther typeCategory = iota
+	categoryInt
+	categoryUint
+	categoryFloat
+	categoryDecimal
+	categoryBool
+	categoryTime
+	categoryText
+)
+
+// categoryOf maps a ColumnType.DatabaseType (Postgres or MySQL) to its category.
+func categoryOf(dbType string) typeCategory {
+	if strings.HasPrefix(dbType, "UNSIGNED ") {
+		switch strings.TrimPrefix(dbType, "UNSIGNED ") {
+		case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT":
+			return categoryUint
+		}
+	}
+
+	switch dbType {
+	case "INT2", "INT4", "INT8", "OID",
+		"TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "YEAR":
+		return categoryInt
+	case "FLOAT4", "FLOAT8", "FLOAT", "DOUBLE", "REAL":
+		return categoryFloat
+	case "NUMERIC", "DECIMAL":
+		return categoryDecimal
+	case "BOOL", "BOOLEAN", "BIT": // MySQL BIT(1) is its boolean column
+		return categoryBool
+	case "DATE", "TIMESTAMP", "TIMESTAMPTZ", "DATETIME":
+		return categoryTime
+	case "TEXT", "VARCHAR", "BPCHAR", "NAME", "CHAR", "CITEXT",
+		"TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "ENUM", "SET":
+		return categoryText
+	default:
+		return categoryOther
+	}
+}
+
+// mysqlTimeLayouts are the text formats MySQL uses for DATE / DATETIME /
+// TIMESTAMP when the DSN does not set parseTime=true.
+var mysqlTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// normalizeValue converts a driver-returned value to the predictable Go
+// type for its database type, using a handler from RegisterScanner when
+// there is one. NULL (nil) is always returned unchanged.
+func normalizeValue(v any, dbType string) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if fn := scannerFor(dbType); fn != nil {
+		return fn(v)
+	}
+	if isArrayType(dbType) {
+		return normalizeArray(v, dbType)
+	}
+
+	switch categoryOf(dbType) {
+	case categoryInt:
+		return toInt64(v)
+	case categoryUint:
+		return toUint64(v)
+	case categoryFloat:
+		return toFloat64(v)
+	case categoryDecimal:
+		return toDecimalString(v)
+	case categoryBool:
+		return toBool(v)
+	case categoryTime:
+		return toTime(v)
+	case categoryText:
+		if b, ok := v.([]byte); ok {
+			return string(b), nil
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// isArrayType reports whether dbType is a Postgres array type, which pgx
+// names after the element type with a leading underscore ("_INT4").
+func isArrayType(dbType string) bool {
+	return strings.HasPrefix(dbType, "_")
+}
+
+// normalizeArray converts a Postgres integer array to []int64 and a text
+// array to []string. Other element types, and arrays containing NULL
+// elements (which have no representation in those slices), are returned
+// unchanged.
+func normalizeArray(v any, dbType string) (any, error) {
+	elems, ok := v.([]any)
+	if !ok {
+		return v, nil
+	}
+	for _, e := range elems {
+		if e == nil {
+			return v, nil
+		}
+	}
+
+	switch categoryOf(strings.TrimPrefix(dbType, "_")) {
+	case categoryInt:
+		out := make([]int64, len(elems))
+		for i, e := range elems {
+			n, err := toInt64(e)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = n.(int64)
+		}
+		return out, nil
+	case categoryText:
+		out := make([]string, len(elems))
+		for i, e := range elems {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("cannot convert array element %T to string", e)
+			}
+			out[i] = s
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+func toInt64(v any) (any, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int32:
+		return int64(n), nil
+	case int16:
+		return int64(n), nil
+	case int8:
+		return int64(n), nil
+	case int:
+		return int64(n), nil
+	case uint32:
+		return int64(n), nil
+	case []byte:
+		return strconv.ParseInt(string(n), 10, 64)
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to int64", v)
+	}
+}
+
+func toUint64(v any) (any, error) {
+	switch n := v.(type) {
+	case uint64:
+		return n, nil
+	case int64:
+		return uint64(n), nil
+	case []byte:
+		return strconv.ParseUint(string(n), 10, 64)
+	case string:
+		return strconv.ParseUint(n, 10, 64)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to uint64", v)
+	}
+}
+
+func toFloat64(v any) (any, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case []byte:
+		return strconv.ParseFloat(string(n), 64)
+	case string:
+		return strconv.ParseFloat(n, 64)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to float64", v)
+	}
+}
+
+// toDecimalString keeps decimals as exact strings — converting to float64
+// would silently lose precision on values like NUMERIC(38,10).
+func toDecimalString(v any) (any, error) {
+	switch n := v.(type) {
+	case string:
+		return n, nil
+	case []byte:
+		return string(n), nil
+	case driver.Valuer: // pgtype.Numeric
+		dv, err := n.Value()
+		if err != nil {
+			return nil, err
+		}
+		if dv == nil {
+			return nil, nil
+		}
+		return fmt.Sprint(dv), nil
+	case float64, float32, int64, int32:
+		return fmt.Sprint(n), nil
+	default:
+		return nil, fmt.Errorf("cannot convert %T to decimal string", v)
+	}
+}
+
+func toBool(v any) (any, error) {
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case int64:
+		return b != 0, nil
+	case []byte:
+		// MySQL BIT(1) arrives as a single raw byte; text protocol as "0"/"1".
+		if len(b) == 1 && b[0] <= 1 {
+			return b[0] == 1, nil
+		}
+		return strconv.ParseBool(string(b))
+	case string:
+		return strconv.ParseBool(b)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to bool", v)
+	}
+}
+
+func toTime(v any) (any, error) {
+	var s string
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case []byte:
+		s = string(t)
+	case string:
+		s = t
+	default:
+		return nil, fmt.Errorf("cannot convert %T to time.Time", v)
+	}
+
+	for _, layout := range mysqlTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot parse %q as time", s)
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// OpenFunc connects to a database using cfg. Each driver package registers
+// one with Register.
+type OpenFunc func(ctx context.Context, cfg *Config) (DB, error)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[Driver]OpenFunc{}
+)
+
+// Register makes a driver available to Open under name. Driver packages
+// call it from init, so — as with database/sql — a program enables a driver
+// by importing its package, usually for side effects only:
+//
+//	import _ "github.com/koustreak/DatRi/internal/database/postgres"
+//
+// Register panics if open is nil or name is already registered.
+func Register(name Driver, open OpenFunc) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	if open == nil {
+		panic("database: Register open func is nil")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("database: Register called twice for driver " + string(name))
+	}
+	drivers[name] = open
+}
+
+// Open connects to the database described by cfg using the driver
+// registered for cfg.Driver, and returns it behind the DB interface.
+// It returns ErrKindInvalidInput if no such driver has been registered.
+//
+// With cfg.AutoReconnect set, the returned DB replaces its pool when every
+// connection has been lost, e.g. after a database restart or failover.
+func Open(ctx context.Context, cfg *Config) (DB, error) {
+	driversMu.RLock()
+	open, ok := drivers[cfg.Driver]
+	driversMu.RUnlock()
+
+	if !ok {
+		return nil, errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("unknown database driver %q (registered: %s) — is its package imported?",
+				cfg.Driver, strings.Join(registeredDrivers(), ", ")))
+	}
+	db, err := open(ctx, cfg)
+	if err != nil || !cfg.AutoReconnect {
+		return db, err
+	}
+	return newReconnectDB(db, func(ctx context.Context) (DB, error) {
+		return open(ctx, cfg)
+	}, cfg.ReconnectWindow), nil
+}
+
+func registeredDrivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// Page is one page of results plus the metadata a list endpoint needs.
+type Page struct {
+	Items      []map[string]any `json:"items"`
+	Total      int64            `json:"total"`       // rows matched across all pages
+	Page       int              `json:"page"`        // 1-based page number
+	PageSize   int              `json:"page_size"`   // requested rows per page
+	TotalPages int              `json:"total_pages"` // 0 when Total is 0
+}
+
+// Paginate runs page number page (1-based) of b, pageSize rows per page,
+// and the matching COUNT query, returning both as a Page.
+//
+// b's filters and ORDER BY are kept; its own LIMIT and OFFSET are replaced.
+// Give b a deterministic ORDER BY, or rows may move between pages. b is
+// not modified. The two queries run separately, so under concurrent writes
+// Total can differ slightly from what the pages contain.
+func Paginate(ctx context.Context, db DB, b *SelectBuilder, page, pageSize int) (*Page, error) {
+	if page < 1 {
+		return nil, errs.New(errs.ErrKindInvalidInput, fmt.Sprintf("page must be >= 1, got %d", page))
+	}
+	if pageSize < 1 {
+		return nil, errs.New(errs.ErrKindInvalidInput, fmt.Sprintf("page size must be >= 1, got %d", pageSize))
+	}
+
+	// A UNION's LIMIT would bind to its first branch, so page over the
+	// combined result as a derived table instead.
+	q := b.Clone()
+	if len(b.unions) > 0 {
+		q = &SelectBuilder{dialect: b.dialect, idents: b.idents, fromSub: b.Clone()}
+	}
+	q.Limit(pageSize).Offset((page - 1) * pageSize)
+
+	total, err := count(ctx, db, b.CountQuery())
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args, err := q.Build()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	items, err := ScanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// count runs a COUNT(*) builder and returns the single value.
+func count(ctx context.Context, db DB, b *SelectBuilder) (int64, error) {
+	sql, args, err := b.Build()
+	if err != nil {
+		return 0, err
+	}
+	row, err := db.QueryRow(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	if err := row.Scan(&n); err != nil {
+		return 0, wrapError("failed to read row count", err)
+	}
+	return n, nil
+}
+
+// QueryExists reports whether b matches any row, running b.ExistsQuery():
+//
+//	taken, err := QueryExists(ctx, db, Select("users", db.Dialect()).Where("email", "=", email))
+func QueryExists(ctx context.Context, db DB, b *SelectBuilder) (bool, error) {
+	sql, args, err := b.ExistsQuery().Build()
+	if err != nil {
+		return false, err
+	}
+	row, err := db.QueryRow(ctx, sql, args...)
+	if err != nil {
+		return false, err
+	}
+	var exists bool
+	if err := row.Scan(&exists); err != nil {
+		return false, wrapError("failed to read EXISTS result", err)
+	}
+	return exists, nil
+}
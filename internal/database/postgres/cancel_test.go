@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// openTestDriver connects to the server named by DATRI_TEST_POSTGRES_DSN,
+// skipping the test when it is unset.
+func openTestDriver(t *testing.T) *Driver {
+	t.Helper()
+	dsn := os.Getenv("DATRI_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("DATRI_TEST_POSTGRES_DSN not set")
+	}
+	cfg := database.DefaultConfig(dsn)
+	cfg.MinConns = 0
+	d, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return d
+}
+
+// drain reads rows to the end and returns the first error met.
+func drain(rows database.Rows, err error) error {
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// eventually polls cond until it holds or the deadline passes.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// checkGoroutines fails the test if the goroutine count does not settle
+// back to base.
+func checkGoroutines(t *testing.T, base int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= base {
+			return
+		}
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			buf = buf[:runtime.Stack(buf, true)]
+			t.Fatalf("goroutines leaked: %d running, want at most %d\n%s", n, base, buf)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestQueryCancelStopsStatement(t *testing.T) {
+	base := runtime.NumGoroutine()
+	d := openTestDriver(t)
+
+	const stmt = "SELECT pg_sleep(30) AS cancel_test"
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := drain(d.Query(ctx, stmt))
+	if got := errs.KindOf(err); got != errs.ErrKindTimeout {
+		t.Fatalf("Query error kind = %v (%v), want %v", got, err, errs.ErrKindTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled Query returned after %s", elapsed)
+	}
+
+	eventually(t, "the statement to stop", func() bool {
+		var n int
+		err := d.pool.QueryRow(context.Background(), `
+			SELECT COUNT(*)
+			FROM pg_stat_activity
+			WHERE state = 'active'
+			  AND query LIKE $1
+			  AND pid <> pg_backend_pid()`, "%"+stmt+"%").Scan(&n)
+		return err == nil && n == 0
+	})
+	eventually(t, "the pool to drain", func() bool { return d.pool.Stat().AcquiredConns() == 0 })
+
+	d.Close()
+	checkGoroutines(t, base)
+}
+
+func TestQueryCancellableContextDoesNotLeak(t *testing.T) {
+	base := runtime.NumGoroutine()
+	d := openTestDriver(t)
+
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := drain(d.Query(ctx, "SELECT 1"))
+		cancel()
+		if err != nil {
+			t.Fatalf("Query %d: %v", i, err)
+		}
+	}
+	if n := d.pool.Stat().AcquiredConns(); n != 0 {
+		t.Errorf("%d connections still acquired", n)
+	}
+
+	d.Close()
+	checkGoroutines(t, base)
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// afterConnect is the pool's AfterConnect hook. It applies the search path,
+// then the caller's Config.AfterConnect.
+func (d *Driver) afterConnect(ctx context.Context, conn *pgx.Conn) error {
+	d.mu.RLock()
+	path := d.searchPath
+	d.mu.RUnlock()
+
+	if len(path) > 0 {
+		idents := make([]string, len(path))
+		for i, s := range path {
+			idents[i] = pgx.Identifier{s}.Sanitize()
+		}
+		if _, err := conn.Exec(ctx, "SET search_path TO "+strings.Join(idents, ", ")); err != nil {
+			return err
+		}
+	}
+
+	if d.onConnect != nil {
+		return d.onConnect(ctx, sessionConn{conn})
+	}
+	return nil
+}
+
+// sessionConn exposes a pgx connection as a database.SessionConn.
+type sessionConn struct {
+	conn *pgx.Conn
+}
+
+func (c sessionConn) Exec(ctx context.Context, sql string, args ...any) error {
+	if _, err := c.conn.Exec(ctx, sql, args...); err != nil {
+		return mapError(err, "session statement failed")
+	}
+	return nil
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/koustreak/DatRi/internal/database"
 	"github.com/koustreak/DatRi/internal/errs"
@@ -15,13 +19,39 @@ import (
 // Driver is a PostgreSQL implementation of database.DB backed by pgxpool.
 // It is safe for concurrent use by multiple goroutines.
 type Driver struct {
-	pool *pgxpool.Pool
+	pool           *pgxpool.Pool
+	acquireTimeout time.Duration // zero: Query waits on the pool as long as ctx allows
+	probeQuery     string
+
+	mu         sync.RWMutex
+	searchPath []string // applied by afterConnect; nil leaves the server default
+
+	onConnect func(context.Context, database.SessionConn) error // Config.AfterConnect
+}
+
+func init() {
+	database.Register(database.DriverPostgres, func(ctx context.Context, cfg *database.Config) (database.DB, error) {
+		d, err := New(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	})
 }
 
 // New connects to PostgreSQL using the provided Config and returns a Driver.
 // It calls Ping to validate the connection before returning.
 func New(ctx context.Context, cfg *database.Config) (*Driver, error) {
-	poolCfg, err := pgxpool.ParseConfig(cfg.DSN)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	dsn, err := cfg.BuildDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	poolCfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, errs.Wrap(errs.ErrKindConnectionFailed, "invalid DSN", err)
 	}
@@ -32,12 +62,20 @@ func New(ctx context.Context, cfg *database.Config) (*Driver, error) {
 	poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
 	poolCfg.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
 
+	d := &Driver{acquireTimeout: cfg.AcquireTimeout, probeQuery: cfg.ProbeQuery, onConnect: cfg.AfterConnect}
+	if d.probeQuery == "" {
+		d.probeQuery = database.DefaultProbeQuery
+	}
+	if sp, ok := poolCfg.ConnConfig.RuntimeParams["search_path"]; ok {
+		d.searchPath = parseSearchPath(sp)
+	}
+	poolCfg.AfterConnect = d.afterConnect
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, errs.Wrap(errs.ErrKindConnectionFailed, "failed to create connection pool", err)
 	}
-
-	d := &Driver{pool: pool}
+	d.pool = pool
 
 	if err := d.Ping(ctx); err != nil {
 		pool.Close()
@@ -57,36 +95,226 @@ func (d *Driver) Ping(ctx context.Context) error {
 	return nil
 }
 
+// Probe runs the probe query to completion, discarding its rows.
+func (d *Driver) Probe(ctx context.Context) error {
+	rows, err := d.Query(ctx, d.probeQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
 // Close drains the connection pool. Call when the application shuts down.
 func (d *Driver) Close() {
 	d.pool.Close()
 }
 
+// Dialect returns database.DialectPostgres.
+func (d *Driver) Dialect() database.Dialect {
+	return database.DialectPostgres
+}
+
 // Query executes a SQL statement that returns multiple rows.
 func (d *Driver) Query(ctx context.Context, sql string, args ...any) (database.Rows, error) {
-	rows, err := d.pool.Query(ctx, sql, args...)
+	sql = database.TagQuery(ctx, sql)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	if d.acquireTimeout == 0 {
+		rows, err := d.pool.Query(ctx, sql, args...)
+		if err != nil {
+			return nil, mapError(err, "query failed")
+		}
+		return &pgxRows{rows: rows}, nil
+	}
+
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.Query(ctx, sql, args...)
 	if err != nil {
+		conn.Release()
 		return nil, mapError(err, "query failed")
 	}
-	return &pgxRows{rows: rows}, nil
+	return &pgxRows{rows: rows, release: conn.Release}, nil
 }
 
 // QueryRow executes a SQL statement expected to return at most one row.
 func (d *Driver) QueryRow(ctx context.Context, sql string, args ...any) (database.Row, error) {
-	row := d.pool.QueryRow(ctx, sql, args...)
-	return &pgxRow{row: row}, nil
+	sql = database.TagQuery(ctx, sql)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	if d.acquireTimeout == 0 {
+		return &pgxRow{row: d.pool.QueryRow(ctx, sql, args...)}, nil
+	}
+
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pgxRow{row: conn.QueryRow(ctx, sql, args...), release: conn.Release}, nil
+}
+
+// ExecResult executes a statement that returns no rows. LastInsertID is
+// always zero: Postgres reports generated keys through RETURNING.
+func (d *Driver) ExecResult(ctx context.Context, sql string, args ...any) (*database.Result, error) {
+	sql = database.TagQuery(ctx, sql)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	if d.acquireTimeout == 0 {
+		tag, err := d.pool.Exec(ctx, sql, args...)
+		if err != nil {
+			return nil, mapError(err, "exec failed")
+		}
+		return &database.Result{RowsAffected: tag.RowsAffected()}, nil
+	}
+
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	tag, err := conn.Exec(ctx, sql, args...)
+	if err != nil {
+		return nil, mapError(err, "exec failed")
+	}
+	return &database.Result{RowsAffected: tag.RowsAffected()}, nil
+}
+
+// acquire takes a connection from the pool, waiting at most acquireTimeout.
+// The timeout covers only the wait: the query itself runs under ctx.
+func (d *Driver) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	acquireCtx, cancel := context.WithTimeout(ctx, d.acquireTimeout)
+	defer cancel()
+
+	conn, err := d.pool.Acquire(acquireCtx)
+	if err != nil {
+		if ctx.Err() == nil && acquireCtx.Err() != nil {
+			return nil, errs.Wrap(errs.ErrKindTimeout,
+				fmt.Sprintf("no pooled connection available within %s", d.acquireTimeout), err)
+		}
+		return nil, mapError(err, "failed to acquire connection")
+	}
+	return conn, nil
 }
 
-// ListTables returns all user-defined table names in the public schema.
+// ListTables returns all user-defined table names in the default schema
+// (public unless a search path is set; see SetSearchPath).
 func (d *Driver) ListTables(ctx context.Context) ([]string, error) {
+	return d.listTables(ctx, d.schema())
+}
+
+// TableExists reports whether a table with the given name exists in the default schema.
+func (d *Driver) TableExists(ctx context.Context, table string) (bool, error) {
+	return d.tableExists(ctx, d.schema(), table)
+}
+
+// InspectSchema introspects the full default schema and returns a *database.Schema.
+// This is intentionally expensive — callers must cache the result.
+func (d *Driver) InspectSchema(ctx context.Context) (*database.Schema, error) {
+	return d.inspectSchema(ctx, d.schema(), database.InspectOptions{})
+}
+
+// InspectSchemaWith is InspectSchema limited to the tables opts selects.
+func (d *Driver) InspectSchemaWith(ctx context.Context, opts database.InspectOptions) (*database.Schema, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return d.inspectSchema(ctx, d.schema(), opts)
+}
+
+// InspectTable introspects a single table. It returns ErrKindNotFound when
+// the table does not exist.
+func (d *Driver) InspectTable(ctx context.Context, table string) (*database.TableInfo, error) {
+	schema := d.schema()
+	exists, err := d.tableExists(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errs.New(errs.ErrKindNotFound, fmt.Sprintf("table %q does not exist", table))
+	}
+	return d.inspectTable(ctx, schema, table)
+}
+
+// --- multi-schema introspection ---
+
+// ListSchemas returns the names of all user schemas in the database,
+// excluding pg_catalog, information_schema and other pg_* system schemas.
+func (d *Driver) ListSchemas(ctx context.Context) ([]string, error) {
+	const q = `
+		SELECT nspname
+		FROM pg_namespace
+		WHERE nspname NOT LIKE 'pg\_%'
+		  AND nspname <> 'information_schema'
+		ORDER BY nspname`
+
+	rows, err := d.pool.Query(ctx, q)
+	if err != nil {
+		return nil, mapError(err, "failed to list schemas")
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, mapError(err, "failed to scan schema name")
+		}
+		schemas = append(schemas, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError(err, "error iterating schemas")
+	}
+	return schemas, nil
+}
+
+// InspectSchemaNamed is InspectSchema for the named Postgres schema
+// instead of the default one. Table names in the result are unqualified.
+// A schema that does not exist yields an empty Schema.
+func (d *Driver) InspectSchemaNamed(ctx context.Context, schemaName string) (*database.Schema, error) {
+	return d.inspectSchema(ctx, schemaName, database.InspectOptions{})
+}
+
+func (d *Driver) inspectSchema(ctx context.Context, schemaName string, opts database.InspectOptions) (*database.Schema, error) {
+	tables, err := d.listTables(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &database.Schema{
+		Tables: make(map[string]*database.TableInfo, len(tables)),
+	}
+
+	for _, tableName := range tables {
+		if !opts.Matches(tableName) {
+			continue
+		}
+		info, err := d.inspectTable(ctx, schemaName, tableName)
+		if err != nil {
+			return nil, fmt.Errorf("inspecting table %q: %w", tableName, err)
+		}
+		schema.Tables[tableName] = info
+	}
+
+	return schema, nil
+}
+
+func (d *Driver) listTables(ctx context.Context, schema string) ([]string, error) {
 	const q = `
 		SELECT table_name
 		FROM information_schema.tables
-		WHERE table_schema = 'public'
+		WHERE table_schema = $1
 		  AND table_type   = 'BASE TABLE'
 		ORDER BY table_name`
 
-	rows, err := d.pool.Query(ctx, q)
+	rows, err := d.pool.Query(ctx, q, schema)
 	if err != nil {
 		return nil, mapError(err, "failed to list tables")
 	}
@@ -106,17 +334,16 @@ func (d *Driver) ListTables(ctx context.Context) ([]string, error) {
 	return tables, nil
 }
 
-// TableExists reports whether a table with the given name exists in the public schema.
-func (d *Driver) TableExists(ctx context.Context, table string) (bool, error) {
+func (d *Driver) tableExists(ctx context.Context, schema, table string) (bool, error) {
 	const q = `
 		SELECT 1
 		FROM information_schema.tables
-		WHERE table_schema = 'public'
+		WHERE table_schema = $1
 		  AND table_type   = 'BASE TABLE'
-		  AND table_name   = $1`
+		  AND table_name   = $2`
 
 	var exists int
-	err := d.pool.QueryRow(ctx, q, table).Scan(&exists)
+	err := d.pool.QueryRow(ctx, q, schema, table).Scan(&exists)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return false, nil
@@ -126,46 +353,33 @@ func (d *Driver) TableExists(ctx context.Context, table string) (bool, error) {
 	return true, nil
 }
 
-// InspectSchema introspects the full public schema and returns a *database.Schema.
-// This is intentionally expensive — callers must cache the result.
-func (d *Driver) InspectSchema(ctx context.Context) (*database.Schema, error) {
-	tables, err := d.ListTables(ctx)
+func (d *Driver) inspectTable(ctx context.Context, schema, table string) (*database.TableInfo, error) {
+	columns, err := d.fetchColumns(ctx, schema, table)
 	if err != nil {
 		return nil, err
 	}
 
-	schema := &database.Schema{
-		Tables: make(map[string]*database.TableInfo, len(tables)),
-	}
-
-	for _, tableName := range tables {
-		info, err := d.inspectTable(ctx, tableName)
-		if err != nil {
-			return nil, fmt.Errorf("inspecting table %q: %w", tableName, err)
-		}
-		schema.Tables[tableName] = info
+	pks, err := d.fetchPrimaryKeys(ctx, schema, table)
+	if err != nil {
+		return nil, err
 	}
 
-	return schema, nil
-}
-
-func (d *Driver) inspectTable(ctx context.Context, table string) (*database.TableInfo, error) {
-	columns, err := d.fetchColumns(ctx, table)
+	uniqueCols, err := d.fetchUniqueColumns(ctx, schema, table)
 	if err != nil {
 		return nil, err
 	}
 
-	pks, err := d.fetchPrimaryKeys(ctx, table)
+	fks, err := d.fetchForeignKeys(ctx, schema, table)
 	if err != nil {
 		return nil, err
 	}
 
-	uniqueCols, err := d.fetchUniqueColumns(ctx, table)
+	comment, err := d.fetchTableComment(ctx, schema, table)
 	if err != nil {
 		return nil, err
 	}
 
-	fks, err := d.fetchForeignKeys(ctx, table)
+	indexes, err := d.fetchIndexes(ctx, schema, table)
 	if err != nil {
 		return nil, err
 	}
@@ -182,21 +396,36 @@ func (d *Driver) inspectTable(ctx context.Context, table string) (*database.Tabl
 		Columns:     columns,
 		PrimaryKey:  pks,
 		ForeignKeys: fks,
+		Indexes:     indexes,
+		Comment:     comment,
 	}, nil
 }
 
-func (d *Driver) fetchColumns(ctx context.Context, table string) ([]*database.ColumnInfo, error) {
+func (d *Driver) fetchColumns(ctx context.Context, schema, table string) ([]*database.ColumnInfo, error) {
+	// Columns using the database default collation report "default" in
+	// pg_collation; resolve it to the database's actual locale. Postgres has
+	// one character set per database, so collatable columns report that.
 	const q = `
-		SELECT column_name,
-		       data_type,
-		       is_nullable = 'YES',
-		       column_default
-		FROM information_schema.columns
-		WHERE table_schema = 'public'
-		  AND table_name   = $1
-		ORDER BY ordinal_position`
-
-	rows, err := d.pool.Query(ctx, q, table)
+		SELECT c.column_name,
+		       c.data_type,
+		       c.is_nullable = 'YES',
+		       c.column_default,
+		       col_description(a.attrelid, a.attnum),
+		       CASE WHEN coll.collname = 'default' THEN db.datcollate::text ELSE coll.collname::text END,
+		       CASE WHEN a.attcollation <> 0 THEN pg_encoding_to_char(db.encoding)::text END,
+		       c.numeric_precision::int,
+		       c.numeric_scale::int
+		FROM information_schema.columns c
+		JOIN pg_attribute a
+		  ON a.attrelid = format('%I.%I', c.table_schema, c.table_name)::regclass
+		 AND a.attname  = c.column_name
+		LEFT JOIN pg_collation coll ON coll.oid = a.attcollation
+		JOIN pg_database db ON db.datname = current_database()
+		WHERE c.table_schema = $1
+		  AND c.table_name   = $2
+		ORDER BY c.ordinal_position`
+
+	rows, err := d.pool.Query(ctx, q, schema, table)
 	if err != nil {
 		return nil, mapError(err, "failed to fetch columns")
 	}
@@ -205,7 +434,8 @@ func (d *Driver) fetchColumns(ctx context.Context, table string) ([]*database.Co
 	var cols []*database.ColumnInfo
 	for rows.Next() {
 		var c database.ColumnInfo
-		if err := rows.Scan(&c.Name, &c.DataType, &c.Nullable, &c.Default); err != nil {
+		if err := rows.Scan(&c.Name, &c.DataType, &c.Nullable, &c.Default, &c.Comment, &c.Collation, &c.CharSet,
+			&c.NumericPrecision, &c.NumericScale); err != nil {
 			return nil, mapError(err, "failed to scan column info")
 		}
 		cols = append(cols, &c)
@@ -213,7 +443,17 @@ func (d *Driver) fetchColumns(ctx context.Context, table string) ([]*database.Co
 	return cols, rows.Err()
 }
 
-func (d *Driver) fetchPrimaryKeys(ctx context.Context, table string) ([]string, error) {
+func (d *Driver) fetchTableComment(ctx context.Context, schema, table string) (*string, error) {
+	const q = `SELECT obj_description(format('%I.%I', $1::text, $2::text)::regclass, 'pg_class')`
+
+	var comment *string
+	if err := d.pool.QueryRow(ctx, q, schema, table).Scan(&comment); err != nil {
+		return nil, mapError(err, "failed to fetch table comment")
+	}
+	return comment, nil
+}
+
+func (d *Driver) fetchPrimaryKeys(ctx context.Context, schema, table string) ([]string, error) {
 	const q = `
 		SELECT kcu.column_name
 		FROM information_schema.table_constraints tc
@@ -221,14 +461,14 @@ func (d *Driver) fetchPrimaryKeys(ctx context.Context, table string) ([]string,
 		  ON tc.constraint_name = kcu.constraint_name
 		 AND tc.table_schema    = kcu.table_schema
 		WHERE tc.constraint_type = 'PRIMARY KEY'
-		  AND tc.table_schema    = 'public'
-		  AND tc.table_name      = $1
+		  AND tc.table_schema    = $1
+		  AND tc.table_name      = $2
 		ORDER BY kcu.ordinal_position`
 
-	return d.fetchStringList(ctx, q, table, "failed to fetch primary keys")
+	return d.fetchStringList(ctx, q, schema, table, "failed to fetch primary keys")
 }
 
-func (d *Driver) fetchUniqueColumns(ctx context.Context, table string) ([]string, error) {
+func (d *Driver) fetchUniqueColumns(ctx context.Context, schema, table string) ([]string, error) {
 	const q = `
 		SELECT kcu.column_name
 		FROM information_schema.table_constraints tc
@@ -236,28 +476,39 @@ func (d *Driver) fetchUniqueColumns(ctx context.Context, table string) ([]string
 		  ON tc.constraint_name = kcu.constraint_name
 		 AND tc.table_schema    = kcu.table_schema
 		WHERE tc.constraint_type = 'UNIQUE'
-		  AND tc.table_schema    = 'public'
-		  AND tc.table_name      = $1`
+		  AND tc.table_schema    = $1
+		  AND tc.table_name      = $2`
 
-	return d.fetchStringList(ctx, q, table, "failed to fetch unique columns")
+	return d.fetchStringList(ctx, q, schema, table, "failed to fetch unique columns")
 }
 
-func (d *Driver) fetchForeignKeys(ctx context.Context, table string) ([]*database.ForeignKey, error) {
+func (d *Driver) fetchForeignKeys(ctx context.Context, schema, table string) ([]*database.ForeignKey, error) {
+	// information_schema cannot pair the columns of a composite key with
+	// the columns they reference, so read conkey/confkey directly.
 	const q = `
-		SELECT kcu.column_name,
-		       ccu.table_name  AS ref_table,
-		       ccu.column_name AS ref_column
-		FROM information_schema.table_constraints tc
-		JOIN information_schema.key_column_usage kcu
-		  ON tc.constraint_name = kcu.constraint_name
-		 AND tc.table_schema    = kcu.table_schema
-		JOIN information_schema.constraint_column_usage ccu
-		  ON tc.constraint_name = ccu.constraint_name
-		WHERE tc.constraint_type = 'FOREIGN KEY'
-		  AND tc.table_schema    = 'public'
-		  AND tc.table_name      = $1`
-
-	rows, err := d.pool.Query(ctx, q, table)
+		SELECT con.conname,
+		       array_agg(a.attname::text ORDER BY k.ord),
+		       rt.relname,
+		       array_agg(ra.attname::text ORDER BY k.ord),
+		       rc.delete_rule,
+		       rc.update_rule
+		FROM pg_constraint con
+		JOIN pg_class t     ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class rt    ON rt.oid = con.confrelid
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+		JOIN pg_attribute a  ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+		JOIN information_schema.referential_constraints rc
+		  ON rc.constraint_schema = n.nspname
+		 AND rc.constraint_name   = con.conname
+		WHERE con.contype = 'f'
+		  AND n.nspname   = $1
+		  AND t.relname   = $2
+		GROUP BY con.conname, rt.relname, rc.delete_rule, rc.update_rule
+		ORDER BY con.conname`
+
+	rows, err := d.pool.Query(ctx, q, schema, table)
 	if err != nil {
 		return nil, mapError(err, "failed to fetch foreign keys")
 	}
@@ -266,7 +517,7 @@ func (d *Driver) fetchForeignKeys(ctx context.Context, table string) ([]*databas
 	var fks []*database.ForeignKey
 	for rows.Next() {
 		fk := &database.ForeignKey{}
-		if err := rows.Scan(&fk.Column, &fk.RefTable, &fk.RefColumn); err != nil {
+		if err := rows.Scan(&fk.Name, &fk.Columns, &fk.RefTable, &fk.RefColumns, &fk.OnDelete, &fk.OnUpdate); err != nil {
 			return nil, mapError(err, "failed to scan foreign key")
 		}
 		fks = append(fks, fk)
@@ -274,8 +525,43 @@ func (d *Driver) fetchForeignKeys(ctx context.Context, table string) ([]*databas
 	return fks, rows.Err()
 }
 
-func (d *Driver) fetchStringList(ctx context.Context, q, table, errMsg string) ([]string, error) {
-	rows, err := d.pool.Query(ctx, q, table)
+func (d *Driver) fetchIndexes(ctx context.Context, schema, table string) ([]*database.Index, error) {
+	// Expression index keys have attnum 0, match no attribute and come back
+	// as "" so they keep their position in the key.
+	const q = `
+		SELECT i.relname,
+		       ix.indisunique,
+		       array_agg(COALESCE(a.attname::text, '') ORDER BY k.ord)
+		FROM pg_index ix
+		JOIN pg_class t     ON t.oid = ix.indrelid
+		JOIN pg_class i     ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		CROSS JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname = $1
+		  AND t.relname = $2
+		GROUP BY i.relname, ix.indisunique
+		ORDER BY i.relname`
+
+	rows, err := d.pool.Query(ctx, q, schema, table)
+	if err != nil {
+		return nil, mapError(err, "failed to fetch indexes")
+	}
+	defer rows.Close()
+
+	var indexes []*database.Index
+	for rows.Next() {
+		idx := &database.Index{}
+		if err := rows.Scan(&idx.Name, &idx.Unique, &idx.Columns); err != nil {
+			return nil, mapError(err, "failed to scan index")
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, rows.Err()
+}
+
+func (d *Driver) fetchStringList(ctx context.Context, q, schema, table, errMsg string) ([]string, error) {
+	rows, err := d.pool.Query(ctx, q, schema, table)
 	if err != nil {
 		return nil, mapError(err, errMsg)
 	}
@@ -295,13 +581,42 @@ func (d *Driver) fetchStringList(ctx context.Context, q, table, errMsg string) (
 // --- pgx type wrappers ---
 
 type pgxRows struct {
-	rows pgx.Rows
+	rows    pgx.Rows
+	release func() // returns an explicitly acquired connection to the pool; may be nil
+}
+
+// Next releases an acquired connection once the result set is exhausted,
+// as pgxpool does for its own Query.
+func (r *pgxRows) Next() bool {
+	if r.rows.Next() {
+		return true
+	}
+	r.releaseConn()
+	return false
 }
 
-func (r *pgxRows) Next() bool             { return r.rows.Next() }
 func (r *pgxRows) Scan(dest ...any) error { return r.rows.Scan(dest...) }
-func (r *pgxRows) Close()                 { r.rows.Close() }
-func (r *pgxRows) Err() error             { return r.rows.Err() }
+
+func (r *pgxRows) Close() {
+	r.rows.Close()
+	r.releaseConn()
+}
+
+func (r *pgxRows) releaseConn() {
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+}
+
+// Err maps iteration errors so a query cancelled mid-stream reports
+// ErrKindTimeout rather than a generic failure.
+func (r *pgxRows) Err() error {
+	if err := r.rows.Err(); err != nil {
+		return mapError(err, "error during row iteration")
+	}
+	return nil
+}
 
 func (r *pgxRows) Columns() ([]string, error) {
 	descs := r.rows.FieldDescriptions()
@@ -312,11 +627,50 @@ func (r *pgxRows) Columns() ([]string, error) {
 	return cols, nil
 }
 
+// ColumnTypes resolves each field's type OID to its registered pgtype name.
+func (r *pgxRows) ColumnTypes() ([]database.ColumnType, error) {
+	descs := r.rows.FieldDescriptions()
+	types := make([]database.ColumnType, len(descs))
+
+	var typeMap *pgtype.Map
+	if conn := r.rows.Conn(); conn != nil {
+		typeMap = conn.TypeMap()
+	}
+
+	for i, d := range descs {
+		types[i].Name = d.Name
+		if typeMap == nil {
+			continue
+		}
+		if t, ok := typeMap.TypeForOID(d.DataTypeOID); ok {
+			types[i].DatabaseType = strings.ToUpper(t.Name)
+		}
+	}
+	return types, nil
+}
+
 type pgxRow struct {
-	row pgx.Row
+	row     pgx.Row
+	release func() // returns an explicitly acquired connection to the pool; may be nil
 }
 
-func (r *pgxRow) Scan(dest ...any) error { return r.row.Scan(dest...) }
+// Scan maps no-rows, server and cancellation errors; decode errors are
+// returned untouched for the caller to classify.
+func (r *pgxRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+	if err == nil {
+		return nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) || isCancellation(err) {
+		return mapError(err, "query failed")
+	}
+	return err
+}
 
 // --- error mapping ---
 
@@ -326,7 +680,7 @@ func mapError(err error, msg string) *errs.Error {
 		return nil
 	}
 
-	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+	if isCancellation(err) {
 		return errs.Wrap(errs.ErrKindTimeout, msg, err)
 	}
 
@@ -336,16 +690,39 @@ func mapError(err error, msg string) *errs.Error {
 
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
-		kind := errs.ErrKindQueryFailed
-		if len(pgErr.Code) >= 2 && pgErr.Code[:2] == "08" {
-			kind = errs.ErrKindConnectionFailed
-		}
-		return errs.Wrap(kind, fmt.Sprintf("%s: %s", msg, pgErr.Message), err)
+		e := errs.Wrap(classifySQLState(pgErr.Code), fmt.Sprintf("%s: %s", msg, pgErr.Message), err)
+		e.Code = pgErr.Code
+		return e
 	}
 
 	return errs.Wrap(errs.ErrKindConnectionFailed, msg, err)
 }
 
+// classifySQLState maps a Postgres SQLSTATE to an ErrKind.
+func classifySQLState(code string) errs.ErrKind {
+	switch code {
+	case "22001", "22003": // string too long, numeric out of range
+		return errs.ErrKindInvalidInput
+	}
+	switch {
+	case strings.HasPrefix(code, "08"): // connection exception
+		return errs.ErrKindConnectionFailed
+	case strings.HasPrefix(code, "23"): // integrity constraint violation
+		return errs.ErrKindConflict
+	default:
+		return errs.ErrKindQueryFailed
+	}
+}
+
+// isCancellation reports whether err stems from a cancelled or expired
+// context. pgconn.Timeout also catches errors pgx raises when the context is
+// done before or during network I/O, which do not always wrap ctx.Err().
+func isCancellation(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled) ||
+		pgconn.Timeout(err)
+}
+
 func toSet(ss []string) map[string]bool {
 	m := make(map[string]bool, len(ss))
 	for _, s := range ss {
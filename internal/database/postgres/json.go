@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// QueryJSON runs b and returns the whole result as one JSON array of row
+// objects, built by Postgres itself:
+//
+//	SELECT COALESCE(json_agg(t), '[]') FROM (<b>) t
+//
+// The bytes can be written straight to an HTTP response, skipping the
+// scan → map → marshal round trip of ScanRows. An empty result is "[]".
+//
+// json_agg rather than jsonb_agg keeps each object's keys in column order
+// and avoids a jsonb conversion. Postgres does not guarantee that the
+// array follows b's ORDER BY. Values use Postgres' JSON encoding, which
+// can differ from ScanRows + encoding/json: numerics are JSON numbers and
+// timestamps carry a numeric offset ("2024-01-02T03:04:05+00:00").
+//
+// b must be built for DialectPostgres; other dialects fail with
+// ErrKindInvalidInput.
+func (d *Driver) QueryJSON(ctx context.Context, b *database.SelectBuilder) (json.RawMessage, error) {
+	if b.Dialect() != database.DialectPostgres {
+		return nil, errs.New(errs.ErrKindInvalidInput, "QueryJSON needs a builder for DialectPostgres")
+	}
+	inner, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	row, err := d.QueryRow(ctx, "SELECT COALESCE(json_agg(t), '[]') FROM ("+inner+") t", args...)
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	if err := row.Scan(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
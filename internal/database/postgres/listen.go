@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// Notification is a message delivered to a channel by NOTIFY.
+type Notification struct {
+	Channel string
+	Payload string
+	PID     uint32 // backend process ID of the notifying session
+}
+
+const (
+	// listenBuffer is how many notifications Listen queues for a slow
+	// consumer before it stops reading from the server.
+	listenBuffer = 64
+
+	listenMinBackoff = 500 * time.Millisecond
+	listenMaxBackoff = 30 * time.Second
+)
+
+// Listen subscribes to a Postgres NOTIFY channel and streams notifications
+// on the returned Go channel until ctx is cancelled, at which point the Go
+// channel is closed.
+//
+// Listen uses its own connection, outside the pool, so a long-lived
+// subscription never holds a pool slot. If that connection is lost, Listen
+// reconnects with exponential backoff and issues LISTEN again; notifications
+// sent while disconnected are not delivered.
+//
+// The initial connect and LISTEN happen before Listen returns, so a bad
+// channel name or unreachable server is reported as an error.
+func (d *Driver) Listen(ctx context.Context, channel string) (<-chan Notification, error) {
+	if channel == "" {
+		return nil, errs.New(errs.ErrKindInvalidInput, "listen channel must not be empty")
+	}
+
+	conn, err := d.listenConn(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Notification, listenBuffer)
+	go d.listenLoop(ctx, conn, channel, out)
+	return out, nil
+}
+
+// listenConn opens a dedicated connection and subscribes it to channel.
+func (d *Driver) listenConn(ctx context.Context, channel string) (*pgx.Conn, error) {
+	conn, err := pgx.ConnectConfig(ctx, d.pool.Config().ConnConfig)
+	if err != nil {
+		return nil, mapError(err, "failed to open listen connection")
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		closeConn(conn)
+		return nil, mapError(err, "failed to listen on channel "+channel)
+	}
+	return conn, nil
+}
+
+func (d *Driver) listenLoop(ctx context.Context, conn *pgx.Conn, channel string, out chan<- Notification) {
+	defer close(out)
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			closeConn(conn)
+			if ctx.Err() != nil {
+				return
+			}
+			if conn = d.relisten(ctx, channel); conn == nil {
+				return // ctx cancelled while reconnecting
+			}
+			continue
+		}
+
+		select {
+		case out <- Notification{Channel: n.Channel, Payload: n.Payload, PID: n.PID}:
+		case <-ctx.Done():
+			closeConn(conn)
+			return
+		}
+	}
+}
+
+// relisten reconnects and resubscribes, backing off between attempts.
+// It returns nil only when ctx is cancelled.
+func (d *Driver) relisten(ctx context.Context, channel string) *pgx.Conn {
+	backoff := listenMinBackoff
+	for {
+		if conn, err := d.listenConn(ctx, channel); err == nil {
+			return conn
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+// closeConn closes a listen connection. It uses a fresh context because
+// the caller's is often already cancelled.
+func closeConn(conn *pgx.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = conn.Close(ctx)
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// SetSearchPath sets the schema search path used by every pooled
+// connection, and makes schemas[0] the default schema for ListTables,
+// TableExists, InspectSchema and InspectTable.
+//
+// New connections apply it in the pool's AfterConnect hook. Existing
+// connections are retired — idle ones immediately, busy ones when they are
+// returned — so no query observes the old path after SetSearchPath returns.
+// Schemas that do not exist are skipped by Postgres, as with SET.
+//
+// A search_path given in the DSN (e.g. ?search_path=app,public) sets the
+// default schema the same way without a call.
+func (d *Driver) SetSearchPath(ctx context.Context, schemas ...string) error {
+	if len(schemas) == 0 {
+		return errs.New(errs.ErrKindInvalidInput, "search path must name at least one schema")
+	}
+	for _, s := range schemas {
+		if s == "" {
+			return errs.New(errs.ErrKindInvalidInput, "search path schema must not be empty")
+		}
+	}
+
+	d.mu.Lock()
+	d.searchPath = append([]string(nil), schemas...)
+	d.mu.Unlock()
+
+	d.pool.Reset()
+	return d.Ping(ctx)
+}
+
+// schema returns the default schema for introspection: the first schema
+// of the search path set by SetSearchPath or the DSN, else "public".
+// "$user" entries are skipped, as they depend on the session's role.
+func (d *Driver) schema() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, s := range d.searchPath {
+		if s != "$user" {
+			return s
+		}
+	}
+	return "public"
+}
+
+// parseSearchPath splits a search_path setting such as `"$user", app` into
+// schema names, unquoting quoted identifiers.
+func parseSearchPath(setting string) []string {
+	var schemas []string
+	for _, part := range strings.Split(setting, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) >= 2 && part[0] == '"' && part[len(part)-1] == '"' {
+			part = strings.ReplaceAll(part[1:len(part)-1], `""`, `"`)
+		}
+		if part != "" {
+			schemas = append(schemas, part)
+		}
+	}
+	return schemas
+}
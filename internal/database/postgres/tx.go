@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/koustreak/DatRi/internal/database"
+)
+
+// Begin starts a transaction with the server's default isolation level.
+func (d *Driver) Begin(ctx context.Context) (database.Tx, error) {
+	return d.BeginTx(ctx, database.TxOptions{})
+}
+
+// BeginTx starts a transaction with the given isolation level and access mode.
+func (d *Driver) BeginTx(ctx context.Context, opts database.TxOptions) (database.Tx, error) {
+	txOpts := pgx.TxOptions{}
+	switch opts.IsolationLevel {
+	case database.IsolationReadCommitted:
+		txOpts.IsoLevel = pgx.ReadCommitted
+	case database.IsolationRepeatableRead:
+		txOpts.IsoLevel = pgx.RepeatableRead
+	case database.IsolationSerializable:
+		txOpts.IsoLevel = pgx.Serializable
+	}
+	if opts.ReadOnly {
+		txOpts.AccessMode = pgx.ReadOnly
+	}
+
+	if d.acquireTimeout == 0 {
+		tx, err := d.pool.BeginTx(ctx, txOpts)
+		if err != nil {
+			return nil, mapError(err, "failed to begin transaction")
+		}
+		return &pgxTx{tx: tx}, nil
+	}
+
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTx(ctx, txOpts)
+	if err != nil {
+		conn.Release()
+		return nil, mapError(err, "failed to begin transaction")
+	}
+	return &pgxTx{tx: tx, release: conn.Release}, nil
+}
+
+// pgxTx implements database.Tx on top of pgx.Tx.
+//
+// Savepoints are issued as plain SQL rather than through pgx's pseudo-nested
+// transactions: those auto-name their savepoints and close them on
+// rollback, so they cannot model named savepoints that survive RollbackTo.
+type pgxTx struct {
+	tx      pgx.Tx
+	release func() // returns an explicitly acquired connection to the pool; may be nil
+}
+
+// done releases an explicitly acquired connection once the transaction has
+// ended. Later calls are no-ops, so a deferred Rollback after Commit is safe.
+func (t *pgxTx) done() {
+	if t.release != nil {
+		t.release()
+		t.release = nil
+	}
+}
+
+func (t *pgxTx) Query(ctx context.Context, sql string, args ...any) (database.Rows, error) {
+	sql = database.TagQuery(ctx, sql)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := t.tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, mapError(err, "query failed")
+	}
+	return &pgxRows{rows: rows}, nil
+}
+
+func (t *pgxTx) QueryRow(ctx context.Context, sql string, args ...any) (database.Row, error) {
+	sql = database.TagQuery(ctx, sql)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	return &pgxRow{row: t.tx.QueryRow(ctx, sql, args...)}, nil
+}
+
+func (t *pgxTx) ExecResult(ctx context.Context, sql string, args ...any) (*database.Result, error) {
+	sql = database.TagQuery(ctx, sql)
+	if err := database.ChargeBudget(ctx); err != nil {
+		return nil, err
+	}
+	tag, err := t.tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return nil, mapError(err, "exec failed")
+	}
+	return &database.Result{RowsAffected: tag.RowsAffected()}, nil
+}
+
+func (t *pgxTx) Commit(ctx context.Context) error {
+	defer t.done()
+	if err := t.tx.Commit(ctx); err != nil {
+		return mapError(err, "commit failed")
+	}
+	return nil
+}
+
+func (t *pgxTx) Rollback(ctx context.Context) error {
+	defer t.done()
+	if err := t.tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		return mapError(err, "rollback failed")
+	}
+	return nil
+}
+
+func (t *pgxTx) Savepoint(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "SAVEPOINT ", name, "failed to create savepoint")
+}
+
+func (t *pgxTx) RollbackTo(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "ROLLBACK TO SAVEPOINT ", name, "failed to roll back to savepoint")
+}
+
+func (t *pgxTx) ReleaseSavepoint(ctx context.Context, name string) error {
+	return t.execSavepoint(ctx, "RELEASE SAVEPOINT ", name, "failed to release savepoint")
+}
+
+func (t *pgxTx) execSavepoint(ctx context.Context, stmt, name, errMsg string) error {
+	if err := database.ValidateSavepointName(name); err != nil {
+		return err
+	}
+	if _, err := t.tx.Exec(ctx, stmt+name); err != nil {
+		return mapError(err, errMsg)
+	}
+	return nil
+}
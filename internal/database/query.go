@@ -2,7 +2,9 @@ package database
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/koustreak/DatRi/internal/errs"
 )
@@ -33,6 +35,21 @@ var validOps = map[string]bool{
 	"ILIKE": true,
 }
 
+// checkOp upper-cases op and checks it against validOps. ILIKE exists only
+// on Postgres; other dialects are pointed at WhereILike instead.
+func checkOp(w *queryWriter, op string) (string, error) {
+	up := strings.ToUpper(op)
+	if !validOps[up] {
+		return "", errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("unsupported WHERE operator: %q", op))
+	}
+	if up == "ILIKE" && w.dialect != DialectPostgres {
+		return "", errs.New(errs.ErrKindInvalidInput,
+			"ILIKE is only supported on Postgres; use WhereILike")
+	}
+	return up, nil
+}
+
 // SelectBuilder constructs a parameterized SELECT query using a fluent API.
 // Values are never interpolated into the SQL string — always passed as args.
 //
@@ -47,12 +64,51 @@ var validOps = map[string]bool{
 //	    Build()
 type SelectBuilder struct {
 	table   string
+	alias   string // table alias set via From
 	dialect Dialect
-	columns []string
-	where   []whereClause
+	columns []selectColumn
+	where   []condition
 	orderBy []orderClause
 	limit   *int
 	offset  *int
+	count   bool           // render SELECT COUNT(*) instead of the column list
+	one     bool           // render SELECT 1 instead of the column list
+	exists  *SelectBuilder // when set, render SELECT EXISTS (subquery) only
+	unions  []unionPart
+	fromSub *SelectBuilder // when set, select FROM (subquery) instead of table
+	lock    *rowLock       // FOR UPDATE / FOR SHARE
+	idents  IdentStyle
+}
+
+// IdentStyle controls how the builder writes identifiers.
+type IdentStyle int
+
+const (
+	// IdentQuoted double-quotes every identifier as written (the default).
+	// On Postgres this makes names case-sensitive: "ID" does not match id.
+	IdentQuoted IdentStyle = iota
+
+	// IdentLowercase lowercases identifiers, then quotes them — matching how
+	// Postgres folds unquoted names, while keeping reserved words safe.
+	IdentLowercase
+
+	// IdentUnquoted writes identifiers bare, letting the database apply its
+	// own case folding. Only plain names (letters, digits and _, not
+	// starting with a digit) are accepted, and reserved words fail. $ is
+	// refused because a bare "price$1" would read as placeholder $1.
+	IdentUnquoted
+)
+
+// selectColumn is one entry of the SELECT list, optionally aliased.
+type selectColumn struct {
+	name  string
+	alias string
+}
+
+// unionPart is a query combined with the builder via UNION [ALL].
+type unionPart struct {
+	all   bool
+	query *SelectBuilder
 }
 
 // SortDirection controls the ORDER BY direction.
@@ -63,15 +119,47 @@ const (
 	Desc SortDirection = true
 )
 
+// condition is a single WHERE predicate. Conditions render themselves into
+// a queryWriter so that placeholders and args always stay in lockstep.
+type condition interface {
+	render(w *queryWriter) error
+}
+
+// whereClause is the standard `column op value` predicate.
 type whereClause struct {
 	column string
 	op     string
 	value  any
 }
 
+func (c whereClause) render(w *queryWriter) error {
+	op, err := checkOp(w, c.op)
+	if err != nil {
+		return err
+	}
+	col, err := w.ident(c.column)
+	if err != nil {
+		return err
+	}
+	w.write(col, " ", op, " ", w.bind(c.value))
+	return nil
+}
+
+// NullsOrder controls where NULLs sort in an ORDER BY key.
+type NullsOrder int
+
+const (
+	// NullsDefault keeps the database's default placement (Postgres: last
+	// for ASC, first for DESC; MySQL: first for ASC, last for DESC).
+	NullsDefault NullsOrder = iota
+	NullsFirst
+	NullsLast
+)
+
 type orderClause struct {
 	column string
 	dir    SortDirection
+	nulls  NullsOrder
 }
 
 // Select starts a new SelectBuilder for the given table and dialect.
@@ -79,24 +167,120 @@ func Select(table string, d Dialect) *SelectBuilder {
 	return &SelectBuilder{table: table, dialect: d}
 }
 
-// Columns restricts the SELECT to the specified columns.
-// If not called, SELECT * is used.
+// Dialect returns the dialect b renders for.
+func (b *SelectBuilder) Dialect() Dialect {
+	return b.dialect
+}
+
+// From sets the table and gives it an alias, so columns can be qualified
+// with the alias in the SELECT list, WHERE and ORDER BY:
+//
+//	Select("", DialectPostgres).From("users", "u").ColumnAs("u.name", "author")
+//	// SELECT "u"."name" AS "author" FROM "users" "u"
+//
+// An empty alias leaves the table unaliased.
+func (b *SelectBuilder) From(table, alias string) *SelectBuilder {
+	b.table = table
+	b.alias = alias
+	return b
+}
+
+// FromSubquery selects from sub as a derived table named alias instead of
+// from a table:
+//
+//	recent := Select("orders", DialectPostgres).OrderBy("created_at", Desc).Limit(100)
+//	Select("", DialectPostgres).FromSubquery(recent, "r").Columns("r.user_id")
+//	// SELECT "r"."user_id" FROM (SELECT * FROM "orders" ORDER BY "created_at" DESC LIMIT $1) AS "r"
+//
+// sub must use the same dialect, checked at Build time, and is copied.
+func (b *SelectBuilder) FromSubquery(sub *SelectBuilder, alias string) *SelectBuilder {
+	b.table = ""
+	b.alias = alias
+	b.fromSub = sub.Clone()
+	return b
+}
+
+// LowercaseIdentifiers makes the builder lowercase every identifier before
+// quoting it, so Columns("ID") matches a Postgres column created as id.
+// See IdentLowercase. Subqueries and UNIONs are rendered with the style of
+// the outermost builder.
+func (b *SelectBuilder) LowercaseIdentifiers() *SelectBuilder {
+	b.idents = IdentLowercase
+	return b
+}
+
+// UnquotedIdentifiers makes the builder write identifiers without quotes,
+// restricted to plain names. See IdentUnquoted. Subqueries and UNIONs are
+// rendered with the style of the outermost builder.
+func (b *SelectBuilder) UnquotedIdentifiers() *SelectBuilder {
+	b.idents = IdentUnquoted
+	return b
+}
+
+// Columns restricts the SELECT to the specified columns, replacing any
+// previously selected. If neither Columns nor ColumnAs is called,
+// SELECT * is used.
 func (b *SelectBuilder) Columns(cols ...string) *SelectBuilder {
-	b.columns = cols
+	b.columns = make([]selectColumn, len(cols))
+	for i, c := range cols {
+		b.columns[i] = selectColumn{name: c}
+	}
+	return b
+}
+
+// ColumnAs appends column to the SELECT list under the name alias
+// (`"column" AS "alias"`). The alias can be used in ORDER BY; SQL does not
+// allow WHERE to reference it.
+func (b *SelectBuilder) ColumnAs(column, alias string) *SelectBuilder {
+	b.columns = append(b.columns, selectColumn{name: column, alias: alias})
 	return b
 }
 
 // Where adds a WHERE condition. op must be one of the allowed comparison
-// operators (=, !=, <, >, <=, >=, LIKE, ILIKE).
+// operators (=, !=, <, >, <=, >=, LIKE, ILIKE). ILIKE is Postgres-only;
+// WhereILike works on every dialect.
 // Multiple calls are combined with AND.
 func (b *SelectBuilder) Where(column, op string, value any) *SelectBuilder {
 	b.where = append(b.where, whereClause{column, op, value})
 	return b
 }
 
+// WhereRaw adds a verbatim SQL predicate, combined with other conditions
+// using AND. Use it for expressions the structured methods cannot express:
+//
+//	WhereRaw("lower(email) = lower(?)", email)
+//	WhereRaw("created_at > now() - $1::interval", "1 day")
+//
+// Placeholders in expr may be written as ? (sequential) or $1, $2, …
+// (positional, relative to args) — never both. They are renumbered to fit
+// the surrounding query and the dialect. When $n placeholders are used, a
+// bare ? is left untouched so Postgres JSONB operators (?, ?|, ?&) work.
+//
+// SECURITY: expr is inserted into the query as-is. Only args are
+// parameterized — never build expr from user input.
+func (b *SelectBuilder) WhereRaw(expr string, args ...any) *SelectBuilder {
+	b.where = append(b.where, rawClause{expr: expr, args: args})
+	return b
+}
+
 // OrderBy appends an ORDER BY clause for the given column and direction.
 func (b *SelectBuilder) OrderBy(column string, dir SortDirection) *SelectBuilder {
-	b.orderBy = append(b.orderBy, orderClause{column, dir})
+	b.orderBy = append(b.orderBy, orderClause{column: column, dir: dir})
+	return b
+}
+
+// OrderByNulls is OrderBy with explicit NULL placement, so ordering on a
+// nullable column is the same on every engine — which stable pagination
+// depends on:
+//
+//	OrderByNulls("deleted_at", Asc, NullsLast)
+//	// Postgres: ORDER BY "deleted_at" ASC NULLS LAST
+//	// MySQL:    ORDER BY ("deleted_at" IS NULL) ASC, "deleted_at" ASC
+//
+// MySQL has no NULLS FIRST/LAST syntax, so the equivalent IS NULL sort key
+// is emitted instead.
+func (b *SelectBuilder) OrderByNulls(column string, dir SortDirection, nulls NullsOrder) *SelectBuilder {
+	b.orderBy = append(b.orderBy, orderClause{column: column, dir: dir, nulls: nulls})
 	return b
 }
 
@@ -112,44 +296,322 @@ func (b *SelectBuilder) Offset(n int) *SelectBuilder {
 	return b
 }
 
+// After applies keyset (cursor) pagination on column: it adds
+// WHERE column > lastValue and ORDER BY column ASC.
+// Combine with Limit for the page size:
+//
+//	Select("users", DialectPostgres).After("id", lastID).Limit(20)
+//	// SELECT * FROM "users" WHERE "id" > $1 ORDER BY "id" ASC LIMIT $2
+//
+// Unlike Offset, the cost does not grow with the page number, because the
+// database seeks straight to lastValue via the column's index.
+func (b *SelectBuilder) After(column string, lastValue any) *SelectBuilder {
+	b.where = append(b.where, whereClause{column, ">", lastValue})
+	b.orderBy = append(b.orderBy, orderClause{column: column, dir: Asc})
+	return b
+}
+
+// Union combines b with other using UNION (duplicate rows removed):
+//
+//	(SELECT … FROM a …) UNION (SELECT … FROM b …)
+//
+// Each side keeps its own WHERE / ORDER BY / LIMIT; placeholders of other
+// continue the numbering of b. Both sides must use the same dialect and
+// select the same number of columns, checked at Build time. other is
+// copied, so later changes to it do not affect b.
+func (b *SelectBuilder) Union(other *SelectBuilder) *SelectBuilder {
+	b.unions = append(b.unions, unionPart{all: false, query: other.Clone()})
+	return b
+}
+
+// UnionAll is like Union but keeps duplicate rows (UNION ALL), which is
+// cheaper because the database does not need to deduplicate.
+func (b *SelectBuilder) UnionAll(other *SelectBuilder) *SelectBuilder {
+	b.unions = append(b.unions, unionPart{all: true, query: other.Clone()})
+	return b
+}
+
+// CountQuery returns a new builder that counts the rows matched by b:
+// SELECT COUNT(*) FROM … WHERE <same conditions>.
+// ORDER BY, LIMIT, OFFSET and row locking are dropped since they are
+// meaningless for a count. b itself is not modified and remains usable.
+//
+// For a builder with UNIONs the whole combined result is counted:
+// SELECT COUNT(*) FROM ((…) UNION (…)) AS "t".
+func (b *SelectBuilder) CountQuery() *SelectBuilder {
+	if len(b.unions) > 0 {
+		return &SelectBuilder{dialect: b.dialect, idents: b.idents, count: true, fromSub: b.Clone()}
+	}
+
+	c := b.Clone()
+	c.count = true
+	c.columns = nil
+	c.orderBy = nil
+	c.limit = nil
+	c.offset = nil
+	c.lock = nil
+	return c
+}
+
+// ExistsQuery returns a new builder that reports whether b matches any
+// row: SELECT EXISTS (SELECT 1 FROM … WHERE <same conditions>). The
+// database stops at the first match, so this is cheaper than counting.
+// ORDER BY, LIMIT, OFFSET and row locking are dropped as in CountQuery; a
+// builder with UNIONs is wrapped whole. b itself is not modified. See
+// QueryExists.
+func (b *SelectBuilder) ExistsQuery() *SelectBuilder {
+	inner := b.Clone()
+	if len(b.unions) == 0 {
+		inner.one = true
+		inner.columns = nil
+		inner.orderBy = nil
+		inner.limit = nil
+		inner.offset = nil
+		inner.lock = nil
+	}
+	return &SelectBuilder{dialect: b.dialect, idents: b.idents, exists: inner}
+}
+
+// Clone returns a deep copy of b. Columns, conditions, ORDER BY keys,
+// UNIONs and the limit/offset pointers are all copied, so a base builder
+// can be cached and extended per request without the variants affecting
+// each other:
+//
+//	base := Select("users", DialectPostgres).Where("active", "=", true)
+//	page := base.Clone().OrderBy("id", Asc).Limit(20)
+//	total := base.CountQuery()
+func (b *SelectBuilder) Clone() *SelectBuilder {
+	c := *b
+	c.columns = append([]selectColumn(nil), b.columns...)
+	c.where = append([]condition(nil), b.where...)
+	c.orderBy = append([]orderClause(nil), b.orderBy...)
+	c.unions = append([]unionPart(nil), b.unions...)
+	if b.fromSub != nil {
+		c.fromSub = b.fromSub.Clone()
+	}
+	if b.exists != nil {
+		c.exists = b.exists.Clone()
+	}
+	if b.limit != nil {
+		n := *b.limit
+		c.limit = &n
+	}
+	if b.offset != nil {
+		n := *b.offset
+		c.offset = &n
+	}
+	return &c
+}
+
 // Build produces the final SQL string and argument slice.
-// Returns an error if any WHERE operator is not in the allowlist.
+// Returns an error if any WHERE operator is not in the allowlist, if
+// Limit/Offset is negative, or if the rendered placeholders do not match
+// the args.
 func (b *SelectBuilder) Build() (string, []any, error) {
+	w := &queryWriter{dialect: b.dialect, idents: b.idents}
+	if err := b.render(w); err != nil {
+		return "", nil, err
+	}
+	sql := w.String()
+	if err := checkPlaceholders(sql, w.args, b.dialect); err != nil {
+		return "", nil, err
+	}
+	return sql, w.args, nil
+}
+
+// checkPlaceholders verifies that the placeholders in the rendered sql
+// line up with args: on Postgres the distinct $n must be exactly $1…$len(args),
+// on MySQL the number of ? must equal len(args). Composition bugs that let
+// them drift would otherwise bind values to the wrong columns silently.
+// Literal ? on Postgres (JSONB operators in WhereRaw) are not placeholders
+// and are ignored.
+func checkPlaceholders(sql string, args []any, d Dialect) error {
+	toks := scanPlaceholders(sql)
+
+	if d == DialectMySQL {
+		n := 0
+		for _, t := range toks {
+			if t.index == 0 {
+				n++
+			}
+		}
+		if n != len(args) {
+			return errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("query has %d placeholder(s) but %d arg(s)", n, len(args)))
+		}
+		return nil
+	}
+
+	seen := make(map[int]bool)
+	for _, t := range toks {
+		if t.index == 0 {
+			continue
+		}
+		if t.index > len(args) {
+			return errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("query references $%d but has only %d arg(s)", t.index, len(args)))
+		}
+		seen[t.index] = true
+	}
+	if len(seen) != len(args) {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("query uses %d distinct placeholder(s) but has %d arg(s)", len(seen), len(args)))
+	}
+	return nil
+}
+
+// render writes the complete statement (including any UNIONs) into w.
+// Sharing the writer lets composed queries continue the placeholder
+// sequence of their parent.
+func (b *SelectBuilder) render(w *queryWriter) error {
+	if b.exists != nil {
+		w.write("SELECT EXISTS (")
+		if err := b.exists.render(w); err != nil {
+			return err
+		}
+		w.write(")")
+		return nil
+	}
+	if len(b.unions) == 0 {
+		return b.renderSelect(w)
+	}
+	if b.lock != nil {
+		return errs.New(errs.ErrKindInvalidInput, "row locking cannot be used with UNION")
+	}
+	for _, u := range b.unions {
+		if u.query.lock != nil {
+			return errs.New(errs.ErrKindInvalidInput, "row locking cannot be used with UNION")
+		}
+	}
+
+	w.write("(")
+	if err := b.renderSelect(w); err != nil {
+		return err
+	}
+	w.write(")")
+
+	for _, u := range b.unions {
+		if err := b.checkUnionCompatible(u.query); err != nil {
+			return err
+		}
+		if u.all {
+			w.write(" UNION ALL (")
+		} else {
+			w.write(" UNION (")
+		}
+		if err := u.query.render(w); err != nil {
+			return err
+		}
+		w.write(")")
+	}
+	return nil
+}
+
+// checkUnionCompatible verifies other can be UNIONed with b.
+// Column counts can only be compared when both sides list their columns,
+// so mixing SELECT * with an explicit list is rejected as unverifiable.
+func (b *SelectBuilder) checkUnionCompatible(other *SelectBuilder) error {
+	if other.dialect != b.dialect {
+		return errs.New(errs.ErrKindInvalidInput, "UNION: both queries must use the same dialect")
+	}
+	left, right := len(b.columns), len(other.columns)
+	if b.count {
+		left = 1
+	}
+	if other.count {
+		right = 1
+	}
+	if (left == 0) != (right == 0) {
+		return errs.New(errs.ErrKindInvalidInput,
+			"UNION: cannot verify column count when only one side uses SELECT *")
+	}
+	if left != right {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("UNION: column count mismatch (%d vs %d)", left, right))
+	}
+	return nil
+}
+
+// renderSelect writes a single SELECT statement (without UNIONs) into w.
+func (b *SelectBuilder) renderSelect(w *queryWriter) error {
+	if b.limit != nil && *b.limit < 0 {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("LIMIT must be >= 0, got %d", *b.limit))
+	}
+	if b.offset != nil && *b.offset < 0 {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("OFFSET must be >= 0, got %d", *b.offset))
+	}
+
 	// --- column list ---
 	cols := "*"
-	if len(b.columns) > 0 {
+	if b.count {
+		cols = "COUNT(*)"
+	} else if b.one {
+		cols = "1"
+	} else if len(b.columns) > 0 {
 		quoted := make([]string, len(b.columns))
 		for i, c := range b.columns {
-			quoted[i] = quoteIdent(c)
+			q, err := w.ident(c.name)
+			if err != nil {
+				return err
+			}
+			if c.alias != "" {
+				a, err := w.alias(c.alias)
+				if err != nil {
+					return err
+				}
+				q += " AS " + a
+			}
+			quoted[i] = q
 		}
 		cols = strings.Join(quoted, ", ")
 	}
 
-	var sb strings.Builder
-	sb.WriteString("SELECT ")
-	sb.WriteString(cols)
-	sb.WriteString(" FROM ")
-	sb.WriteString(quoteIdent(b.table))
-
-	var args []any
-	argIdx := 1
+	w.write("SELECT ", cols, " FROM ")
+	if b.fromSub != nil {
+		if err := checkSubqueryDialect(w, b.fromSub); err != nil {
+			return err
+		}
+		w.write("(")
+		if err := b.fromSub.render(w); err != nil {
+			return err
+		}
+		alias := `"t"`
+		if b.alias != "" {
+			a, err := w.alias(b.alias)
+			if err != nil {
+				return err
+			}
+			alias = a
+		}
+		w.write(") AS ", alias)
+	} else {
+		table, err := w.ident(b.table)
+		if err != nil {
+			return err
+		}
+		w.write(table)
+		if b.alias != "" {
+			a, err := w.alias(b.alias)
+			if err != nil {
+				return err
+			}
+			w.write(" ", a)
+		}
+	}
 
 	// --- WHERE ---
 	if len(b.where) > 0 {
-		parts := make([]string, 0, len(b.where))
-		for _, w := range b.where {
-			op := strings.ToUpper(w.op)
-			if !validOps[op] {
-				return "", nil, errs.New(errs.ErrKindInvalidInput,
-					fmt.Sprintf("unsupported WHERE operator: %q", w.op),
-				)
+		w.write(" WHERE ")
+		for i, c := range b.where {
+			if i > 0 {
+				w.write(" AND ")
+			}
+			if err := c.render(w); err != nil {
+				return err
 			}
-			parts = append(parts, fmt.Sprintf("%s %s %s", quoteIdent(w.column), op, b.placeholder(argIdx)))
-			args = append(args, w.value)
-			argIdx++
 		}
-		sb.WriteString(" WHERE ")
-		sb.WriteString(strings.Join(parts, " AND "))
 	}
 
 	// --- ORDER BY ---
@@ -160,41 +622,226 @@ func (b *SelectBuilder) Build() (string, []any, error) {
 			if o.dir == Desc {
 				dir = "DESC"
 			}
-			parts[i] = fmt.Sprintf("%s %s", quoteIdent(o.column), dir)
+			col, err := w.ident(o.column)
+			if err != nil {
+				return err
+			}
+			parts[i] = renderOrderKey(w.dialect, col, dir, o.nulls)
 		}
-		sb.WriteString(" ORDER BY ")
-		sb.WriteString(strings.Join(parts, ", "))
+		w.write(" ORDER BY ", strings.Join(parts, ", "))
 	}
 
 	// --- LIMIT ---
 	if b.limit != nil {
-		sb.WriteString(fmt.Sprintf(" LIMIT %s", b.placeholder(argIdx)))
-		args = append(args, *b.limit)
-		argIdx++
+		w.write(" LIMIT ", w.bind(*b.limit))
 	}
 
 	// --- OFFSET ---
 	if b.offset != nil {
-		sb.WriteString(fmt.Sprintf(" OFFSET %s", b.placeholder(argIdx)))
-		args = append(args, *b.offset)
+		w.write(" OFFSET ", w.bind(*b.offset))
 	}
 
-	return sb.String(), args, nil
+	// --- FOR UPDATE / FOR SHARE ---
+	return b.renderLock(w)
+}
+
+// renderOrderKey renders one ORDER BY key for an already-quoted column.
+func renderOrderKey(d Dialect, col, dir string, nulls NullsOrder) string {
+	switch {
+	case nulls == NullsDefault:
+		return fmt.Sprintf("%s %s", col, dir)
+	case d == DialectMySQL:
+		// (col IS NULL) is 0 for values and 1 for NULLs.
+		nullsDir := "ASC"
+		if nulls == NullsFirst {
+			nullsDir = "DESC"
+		}
+		return fmt.Sprintf("(%s IS NULL) %s, %s %s", col, nullsDir, col, dir)
+	case nulls == NullsFirst:
+		return fmt.Sprintf("%s %s NULLS FIRST", col, dir)
+	default:
+		return fmt.Sprintf("%s %s NULLS LAST", col, dir)
+	}
+}
+
+// queryWriter accumulates SQL text and its bind arguments while a query is
+// rendered. Every placeholder is produced by bind, so numbering can never
+// drift from the args slice.
+type queryWriter struct {
+	sb      strings.Builder
+	args    []any
+	dialect Dialect
+	idents  IdentStyle
+}
+
+func (w *queryWriter) write(parts ...string) {
+	for _, p := range parts {
+		w.sb.WriteString(p)
+	}
+}
+
+// bind appends v to the args and returns its placeholder.
+func (w *queryWriter) bind(v any) string {
+	w.args = append(w.args, v)
+	return placeholder(w.dialect, len(w.args))
+}
+
+func (w *queryWriter) String() string {
+	return w.sb.String()
+}
+
+// ident renders a (possibly qualified) identifier in the writer's style.
+func (w *queryWriter) ident(name string) (string, error) {
+	return quoteIdent(name, w.idents)
+}
+
+// alias renders a table or column alias in the writer's style.
+func (w *queryWriter) alias(name string) (string, error) {
+	return quoteAlias(name, w.idents)
 }
 
 // placeholder returns the correct parameter placeholder for the dialect.
 // Postgres: $1, $2, …   MySQL: ? (index is ignored)
-func (b *SelectBuilder) placeholder(idx int) string {
-	if b.dialect == DialectMySQL {
+func placeholder(d Dialect, idx int) string {
+	if d == DialectMySQL {
 		return "?"
 	}
 	return fmt.Sprintf("$%d", idx)
 }
 
-// quoteIdent wraps a SQL identifier in double-quotes (ANSI standard).
-// This safely handles reserved words and mixed-case names.
+// quoteIdent quotes a SQL identifier with double-quotes (ANSI standard),
+// which safely handles reserved words and mixed-case names. style can
+// lowercase the name first or leave it unquoted (see IdentStyle).
+//
+// Qualified names are split on "." and each part quoted separately
+// ("users.id" → "users"."id"). A bare "*" — alone or as the last part
+// ("u.*") — is passed through unquoted. Empty parts, control characters and
+// SQL comment / statement separators are rejected with ErrKindInvalidInput.
+// Embedded double-quotes are escaped by doubling.
+//
 // Note: MySQL also accepts double-quoted identifiers when ANSI mode is on,
 // but both drivers work correctly with this quoting style.
-func quoteIdent(name string) string {
-	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+func quoteIdent(name string, style IdentStyle) (string, error) {
+	if err := validateIdent(name); err != nil {
+		return "", err
+	}
+	if style == IdentLowercase {
+		name = strings.ToLower(name)
+	}
+
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		switch {
+		case p == "*" && i == len(parts)-1:
+			// wildcard — leave unquoted
+		case p == "":
+			return "", errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("invalid identifier %q: empty name segment", name))
+		case strings.Contains(p, "*"):
+			return "", errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("invalid identifier %q: misplaced wildcard", name))
+		case style == IdentUnquoted:
+			if !bareIdent.MatchString(p) {
+				return "", errs.New(errs.ErrKindInvalidInput,
+					fmt.Sprintf("invalid identifier %q: %q cannot be used unquoted", name, p))
+			}
+		default:
+			parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+		}
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// quoteAlias quotes a table or column alias, which unlike a column
+// reference must be a single unqualified name.
+func quoteAlias(alias string, style IdentStyle) (string, error) {
+	if strings.ContainsAny(alias, ".*") {
+		return "", errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("invalid alias %q: must be a single unqualified name", alias))
+	}
+	return quoteIdent(alias, style)
+}
+
+// bareIdent matches the names IdentUnquoted writes without quotes.
+var bareIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateIdent rejects identifiers that can only be injection attempts or
+// mistakes. Quoting already neutralises them; failing loudly is clearer.
+func validateIdent(name string) error {
+	if name == "" {
+		return errs.New(errs.ErrKindInvalidInput, "identifier must not be empty")
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("invalid identifier %q: contains control characters", name))
+		}
+	}
+	for _, bad := range []string{";", "--", "/*", "*/"} {
+		if strings.Contains(name, bad) {
+			return errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("invalid identifier %q: contains %q", name, bad))
+		}
+	}
+	return nil
+}
+
+// Debug returns b's SQL with each placeholder replaced by its argument
+// rendered as a literal, for reading in logs and debuggers:
+//
+//	Select("users", DialectPostgres).Where("name", "=", "O'Brien").Debug()
+//	// SELECT * FROM "users" WHERE "name" = 'O''Brien'
+//
+// NEVER EXECUTE THE RESULT. The literal rendering is approximate — it does
+// not follow the server's escaping rules or types exactly — so running it
+// can misbehave or open an injection hole. Use Build for anything sent to
+// a database. If b does not build, Debug returns the build error's text.
+func (b *SelectBuilder) Debug() string {
+	sql, args, err := b.Build()
+	if err != nil {
+		return "-- invalid query: " + err.Error()
+	}
+
+	var sb strings.Builder
+	last, next := 0, 0
+	for _, t := range scanPlaceholders(sql) {
+		idx := t.index - 1
+		if b.dialect == DialectMySQL {
+			if t.index != 0 {
+				continue
+			}
+			idx, next = next, next+1
+		} else if t.index == 0 {
+			continue // a literal ? operator on Postgres
+		}
+		if idx >= len(args) {
+			continue
+		}
+		sb.WriteString(sql[last:t.start])
+		sb.WriteString(debugLiteral(args[idx]))
+		last = t.end
+	}
+	sb.WriteString(sql[last:])
+	return sb.String()
+}
+
+// debugLiteral renders v as a SQL literal for Debug.
+func debugLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999Z07:00") + "'"
+	case []byte:
+		return "'" + strings.ReplaceAll(string(v), "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(fmt.Sprint(v), "'", "''") + "'"
+	}
 }
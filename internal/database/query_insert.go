@@ -0,0 +1,240 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// InsertBuilder constructs a parameterized INSERT statement, optionally an
+// upsert. Values are always passed as args. Run it with ExecResult, or
+// with Query when it has a RETURNING clause.
+//
+// Usage (Postgres):
+//
+//	sql, args, err := Insert("users", DialectPostgres).
+//	    Columns("email", "name").
+//	    Values("a@example.com", "Alice").
+//	    OnConflict("email").DoUpdate("name").
+//	    Build()
+//	// INSERT INTO "users" ("email", "name") VALUES ($1, $2)
+//	//   ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name"
+type InsertBuilder struct {
+	table     string
+	dialect   Dialect
+	idents    IdentStyle
+	columns   []string
+	rows      [][]any
+	conflict  *conflictClause
+	returning []string
+}
+
+// conflictClause is the upsert part of an INSERT.
+type conflictClause struct {
+	target  []string // conflict columns; Postgres only
+	update  []string // columns overwritten with the new row's values
+	nothing bool     // ignore the conflicting row instead
+}
+
+// Insert starts a new InsertBuilder for table.
+func Insert(table string, d Dialect) *InsertBuilder {
+	return &InsertBuilder{table: table, dialect: d}
+}
+
+// Columns sets the columns each row of Values supplies, in order.
+func (b *InsertBuilder) Columns(cols ...string) *InsertBuilder {
+	b.columns = append(b.columns, cols...)
+	return b
+}
+
+// Values adds one row. Call it repeatedly for a multi-row INSERT; each
+// row must have one value per column, checked at Build time.
+func (b *InsertBuilder) Values(vals ...any) *InsertBuilder {
+	b.rows = append(b.rows, vals)
+	return b
+}
+
+// OnConflict turns the INSERT into an upsert for rows that collide with
+// an existing row; finish it with DoUpdate or DoNothing. On Postgres cols
+// name the unique constraint's columns (ON CONFLICT (cols)) and must be
+// given for DoUpdate. MySQL always reacts to any unique key and ignores
+// cols.
+func (b *InsertBuilder) OnConflict(cols ...string) *InsertBuilder {
+	b.conflict = &conflictClause{target: cols}
+	return b
+}
+
+// DoUpdate overwrites setCols of the existing row with the values that
+// were being inserted:
+//
+//	Postgres: ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"
+//	MySQL:    ON DUPLICATE KEY UPDATE "name" = VALUES("name")
+func (b *InsertBuilder) DoUpdate(setCols ...string) *InsertBuilder {
+	if b.conflict == nil {
+		b.conflict = &conflictClause{}
+	}
+	b.conflict.update = append(b.conflict.update, setCols...)
+	return b
+}
+
+// DoNothing skips rows that conflict with an existing row:
+//
+//	Postgres: ON CONFLICT ("id") DO NOTHING
+//	MySQL:    ON DUPLICATE KEY UPDATE "id" = "id"
+//
+// MySQL has no DO NOTHING; assigning the first column to itself leaves
+// the existing row unchanged.
+func (b *InsertBuilder) DoNothing() *InsertBuilder {
+	if b.conflict == nil {
+		b.conflict = &conflictClause{}
+	}
+	b.conflict.nothing = true
+	return b
+}
+
+// Returning adds a RETURNING clause, so the statement yields the listed
+// columns of each inserted row — the way to read generated keys on
+// Postgres. MySQL has no RETURNING; Build rejects it there.
+func (b *InsertBuilder) Returning(cols ...string) *InsertBuilder {
+	b.returning = append(b.returning, cols...)
+	return b
+}
+
+// Build produces the final SQL string and argument slice. It returns an
+// ErrKindInvalidInput error if there are no columns or rows, a row has the
+// wrong number of values, or the upsert is incomplete or unsupported by
+// the dialect.
+func (b *InsertBuilder) Build() (string, []any, error) {
+	if len(b.columns) == 0 {
+		return "", nil, errs.New(errs.ErrKindInvalidInput, "INSERT needs at least one column")
+	}
+	if len(b.rows) == 0 {
+		return "", nil, errs.New(errs.ErrKindInvalidInput, "INSERT needs at least one row of values")
+	}
+	for i, row := range b.rows {
+		if len(row) != len(b.columns) {
+			return "", nil, errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("INSERT row %d has %d value(s) for %d column(s)", i+1, len(row), len(b.columns)))
+		}
+	}
+	if len(b.returning) > 0 && b.dialect != DialectPostgres {
+		return "", nil, errs.New(errs.ErrKindInvalidInput, "RETURNING is only supported on Postgres")
+	}
+	if c := b.conflict; c != nil {
+		if c.nothing == (len(c.update) > 0) {
+			return "", nil, errs.New(errs.ErrKindInvalidInput,
+				"OnConflict needs exactly one of DoUpdate or DoNothing")
+		}
+		if b.dialect == DialectPostgres && len(c.update) > 0 && len(c.target) == 0 {
+			return "", nil, errs.New(errs.ErrKindInvalidInput,
+				"DoUpdate on Postgres needs the conflict columns passed to OnConflict")
+		}
+	}
+
+	w := &queryWriter{dialect: b.dialect, idents: b.idents}
+	if err := b.render(w); err != nil {
+		return "", nil, err
+	}
+	return w.String(), w.args, nil
+}
+
+func (b *InsertBuilder) render(w *queryWriter) error {
+	table, err := w.ident(b.table)
+	if err != nil {
+		return err
+	}
+	cols, err := identList(w, b.columns)
+	if err != nil {
+		return err
+	}
+
+	w.write("INSERT INTO ", table, " (", strings.Join(cols, ", "), ") VALUES ")
+	for i, row := range b.rows {
+		if i > 0 {
+			w.write(", ")
+		}
+		w.write("(")
+		for j, v := range row {
+			if j > 0 {
+				w.write(", ")
+			}
+			w.write(w.bind(v))
+		}
+		w.write(")")
+	}
+
+	if b.conflict != nil {
+		if err := b.renderConflict(w, cols[0]); err != nil {
+			return err
+		}
+	}
+
+	if len(b.returning) > 0 {
+		ret, err := identList(w, b.returning)
+		if err != nil {
+			return err
+		}
+		w.write(" RETURNING ", strings.Join(ret, ", "))
+	}
+	return nil
+}
+
+// renderConflict writes the upsert clause. firstCol is the quoted first
+// insert column, which MySQL's DO NOTHING assigns to itself.
+func (b *InsertBuilder) renderConflict(w *queryWriter, firstCol string) error {
+	c := b.conflict
+	set, err := identList(w, c.update)
+	if err != nil {
+		return err
+	}
+
+	if b.dialect == DialectMySQL {
+		if c.nothing {
+			w.write(" ON DUPLICATE KEY UPDATE ", firstCol, " = ", firstCol)
+			return nil
+		}
+		w.write(" ON DUPLICATE KEY UPDATE ")
+		for i, col := range set {
+			if i > 0 {
+				w.write(", ")
+			}
+			w.write(col, " = VALUES(", col, ")")
+		}
+		return nil
+	}
+
+	w.write(" ON CONFLICT")
+	if len(c.target) > 0 {
+		target, err := identList(w, c.target)
+		if err != nil {
+			return err
+		}
+		w.write(" (", strings.Join(target, ", "), ")")
+	}
+	if c.nothing {
+		w.write(" DO NOTHING")
+		return nil
+	}
+	w.write(" DO UPDATE SET ")
+	for i, col := range set {
+		if i > 0 {
+			w.write(", ")
+		}
+		w.write(col, " = EXCLUDED.", col)
+	}
+	return nil
+}
+
+// identList renders each name with w.ident.
+func identList(w *queryWriter, names []string) ([]string, error) {
+	out := make([]string, len(names))
+	for i, n := range names {
+		q, err := w.ident(n)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = q
+	}
+	return out, nil
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// rawClause is a verbatim predicate added via WhereRaw. It is wrapped in
+// parentheses so an OR inside expr cannot escape the surrounding AND chain,
+// and its placeholders are renumbered into the surrounding query.
+type rawClause struct {
+	expr string
+	args []any
+}
+
+func (c rawClause) render(w *queryWriter) error {
+	toks := scanPlaceholders(c.expr)
+
+	positional := false
+	for _, t := range toks {
+		if t.index > 0 {
+			positional = true
+			break
+		}
+	}
+
+	w.write("(")
+	var err error
+	if positional {
+		err = c.renderPositional(w, toks)
+	} else {
+		err = c.renderSequential(w, toks)
+	}
+	w.write(")")
+	return err
+}
+
+// renderSequential handles expressions written with ? placeholders.
+func (c rawClause) renderSequential(w *queryWriter, toks []placeholderToken) error {
+	if len(toks) != len(c.args) {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("WhereRaw: expression has %d placeholder(s) but %d arg(s) were given", len(toks), len(c.args)))
+	}
+
+	last := 0
+	for i, t := range toks {
+		w.write(c.expr[last:t.start], w.bind(c.args[i]))
+		last = t.end
+	}
+	w.write(c.expr[last:])
+	return nil
+}
+
+// renderPositional handles expressions written with $n placeholders.
+// Postgres reuses one placeholder per distinct $n; MySQL has no positional
+// placeholders, so each occurrence binds its own copy of the arg.
+func (c rawClause) renderPositional(w *queryWriter, toks []placeholderToken) error {
+	used := make([]bool, len(c.args))
+	for _, t := range toks {
+		if t.index == 0 {
+			continue
+		}
+		if t.index > len(c.args) {
+			return errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("WhereRaw: placeholder $%d has no matching arg (%d given)", t.index, len(c.args)))
+		}
+		used[t.index-1] = true
+	}
+	for i, u := range used {
+		if !u {
+			return errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("WhereRaw: arg %d is never referenced in the expression", i+1))
+		}
+	}
+
+	bound := make(map[int]string, len(c.args))
+	last := 0
+	for _, t := range toks {
+		if t.index == 0 {
+			continue // a literal ? (e.g. a JSONB operator) — keep as written
+		}
+		ph, ok := bound[t.index]
+		if !ok || w.dialect == DialectMySQL {
+			ph = w.bind(c.args[t.index-1])
+			bound[t.index] = ph
+		}
+		w.write(c.expr[last:t.start], ph)
+		last = t.end
+	}
+	w.write(c.expr[last:])
+	return nil
+}
+
+// placeholderToken locates a placeholder inside a SQL fragment.
+// index is n for $n, or 0 for ?.
+type placeholderToken struct {
+	start, end int
+	index      int
+}
+
+// scanPlaceholders finds ? and $n placeholders in sql, skipping quoted
+// strings, quoted identifiers, and comments.
+func scanPlaceholders(sql string) []placeholderToken {
+	var toks []placeholderToken
+
+	for i := 0; i < len(sql); i++ {
+		switch ch := sql[i]; {
+		case ch == '\'' || ch == '"' || ch == '`':
+			// Skip to the closing quote; a doubled quote is an escape.
+			for i++; i < len(sql); i++ {
+				if sql[i] == ch {
+					if i+1 < len(sql) && sql[i+1] == ch {
+						i++
+						continue
+					}
+					break
+				}
+			}
+
+		case ch == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+
+		case ch == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := len(sql) - 1
+			for j := i + 2; j+1 < len(sql); j++ {
+				if sql[j] == '*' && sql[j+1] == '/' {
+					end = j + 1
+					break
+				}
+			}
+			i = end
+
+		case ch == '?':
+			toks = append(toks, placeholderToken{start: i, end: i + 1})
+
+		case ch == '$':
+			j := i + 1
+			n := 0
+			for j < len(sql) && sql[j] >= '0' && sql[j] <= '9' {
+				n = n*10 + int(sql[j]-'0')
+				j++
+			}
+			if j > i+1 && n > 0 {
+				toks = append(toks, placeholderToken{start: i, end: j, index: n})
+				i = j - 1
+			}
+		}
+	}
+
+	return toks
+}
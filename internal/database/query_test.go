@@ -0,0 +1,28 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+func TestUnquotedIdentifiersRejectDollar(t *testing.T) {
+	_, _, err := database.Select("products", database.DialectPostgres).
+		UnquotedIdentifiers().
+		Columns("price$1").
+		Build()
+	if !errs.IsInvalidInput(err) {
+		t.Fatalf("Build error = %v, want ErrKindInvalidInput", err)
+	}
+
+	sql, _, err := database.Select("products", database.DialectPostgres).
+		Columns("price$1").
+		Build()
+	if err != nil {
+		t.Fatalf("quoted Build: %v", err)
+	}
+	if want := `SELECT "price$1" FROM "products"`; sql != want {
+		t.Errorf("quoted Build = %q, want %q", sql, want)
+	}
+}
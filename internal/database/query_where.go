@@ -0,0 +1,479 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// WhereInSubquery adds `column IN (<sub>)`, combined with other conditions
+// using AND:
+//
+//	active := Select("active_users", DialectPostgres).Columns("id").Where("plan", "=", "pro")
+//	Select("orders", DialectPostgres).WhereInSubquery("user_id", active)
+//	// SELECT * FROM "orders" WHERE "user_id" IN (SELECT "id" FROM "active_users" WHERE "plan" = $1)
+//
+// The subquery's args are merged into the parent's and its placeholders
+// renumbered. sub must use the same dialect as the parent and pass
+// ValidateSubquery; either problem is reported by Build. sub is copied, so
+// later changes to it have no effect.
+func (b *SelectBuilder) WhereInSubquery(column string, sub *SelectBuilder) *SelectBuilder {
+	b.where = append(b.where, subqueryClause{column: column, sub: sub.Clone()})
+	return b
+}
+
+// WhereColumn adds a comparison between two columns of the same row,
+// combined with other conditions using AND:
+//
+//	Select("orders", DialectPostgres).WhereColumn("updated_at", "<", "created_at")
+//	// SELECT * FROM "orders" WHERE "updated_at" < "created_at"
+//
+// Both sides are quoted as identifiers and no arg is added. op is checked
+// against the same allowlist as Where.
+func (b *SelectBuilder) WhereColumn(leftCol, op, rightCol string) *SelectBuilder {
+	b.where = append(b.where, columnClause{left: leftCol, op: op, right: rightCol})
+	return b
+}
+
+// columnClause is `left op right` with a column on both sides.
+type columnClause struct {
+	left, op, right string
+}
+
+func (c columnClause) render(w *queryWriter) error {
+	op, err := checkOp(w, c.op)
+	if err != nil {
+		return err
+	}
+	left, err := w.ident(c.left)
+	if err != nil {
+		return err
+	}
+	right, err := w.ident(c.right)
+	if err != nil {
+		return err
+	}
+	w.write(left, " ", op, " ", right)
+	return nil
+}
+
+// WhereArrayContains adds `value = ANY(column)` for a Postgres array column,
+// matching rows whose array holds value:
+//
+//	Select("posts", DialectPostgres).WhereArrayContains("tags", "go")
+//	// SELECT * FROM "posts" WHERE $1 = ANY("tags")
+//
+// MySQL has no array type; Build returns ErrKindInvalidInput for it.
+func (b *SelectBuilder) WhereArrayContains(column string, value any) *SelectBuilder {
+	b.where = append(b.where, arrayContainsClause{column: column, value: value})
+	return b
+}
+
+// arrayContainsClause is `value = ANY(column)`.
+type arrayContainsClause struct {
+	column string
+	value  any
+}
+
+func (c arrayContainsClause) render(w *queryWriter) error {
+	if w.dialect != DialectPostgres {
+		return errs.New(errs.ErrKindInvalidInput, "WhereArrayContains: array columns are only supported on Postgres")
+	}
+	col, err := w.ident(c.column)
+	if err != nil {
+		return err
+	}
+	w.write(w.bind(c.value), " = ANY(", col, ")")
+	return nil
+}
+
+// WhereAny adds `column op ANY(values)`, where values is a slice passed as a
+// single array parameter:
+//
+//	Select("users", DialectPostgres).WhereAny("id", "=", []int64{1, 2, 3})
+//	// SELECT * FROM "users" WHERE "id" = ANY($1)
+//
+// Unlike an expanded IN list, the statement text does not change with the
+// number of values, so Postgres can reuse one prepared plan.
+//
+// Other dialects have no array parameters: there, op must be "=" and the
+// values are expanded into `column IN (?, ?, …)`. An empty slice matches
+// no rows on every dialect. op is checked against the same allowlist as Where.
+func (b *SelectBuilder) WhereAny(column, op string, values any) *SelectBuilder {
+	b.where = append(b.where, anyClause{column: column, op: op, values: values})
+	return b
+}
+
+// anyClause is `column op ANY(values)`.
+type anyClause struct {
+	column string
+	op     string
+	values any
+}
+
+func (c anyClause) render(w *queryWriter) error {
+	op, err := checkOp(w, c.op)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(c.values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("WhereAny: values must be a slice, got %T", c.values))
+	}
+	col, err := w.ident(c.column)
+	if err != nil {
+		return err
+	}
+
+	if w.dialect == DialectPostgres {
+		w.write(col, " ", op, " ANY(", w.bind(c.values), ")")
+		return nil
+	}
+
+	if op != "=" {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("WhereAny: operator %q needs array parameters, which this dialect lacks", c.op))
+	}
+	if rv.Len() == 0 {
+		w.write("1 = 0")
+		return nil
+	}
+	w.write(col, " IN (")
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			w.write(", ")
+		}
+		w.write(w.bind(rv.Index(i).Interface()))
+	}
+	w.write(")")
+	return nil
+}
+
+// subqueryClause is `column IN (subquery)`.
+type subqueryClause struct {
+	column string
+	sub    *SelectBuilder
+}
+
+func (c subqueryClause) render(w *queryWriter) error {
+	if err := checkSubqueryDialect(w, c.sub); err != nil {
+		return err
+	}
+	if err := c.sub.ValidateSubquery(); err != nil {
+		return err
+	}
+	col, err := w.ident(c.column)
+	if err != nil {
+		return err
+	}
+	w.write(col, " IN (")
+	if err := c.sub.render(w); err != nil {
+		return err
+	}
+	w.write(")")
+	return nil
+}
+
+// WhereExists adds `EXISTS (<sub>)`, combined with other conditions using
+// AND. Correlate sub with the outer query through table aliases:
+//
+//	orders := Select("", DialectPostgres).From("orders", "o").
+//	    Columns("o.id").WhereColumn("o.user_id", "=", "u.id")
+//	Select("", DialectPostgres).From("users", "u").WhereExists(orders)
+//	// SELECT * FROM "users" "u" WHERE EXISTS (SELECT "o"."id" FROM "orders" "o" WHERE "o"."user_id" = "u"."id")
+//
+// Unlike a join, each outer row appears at most once. Args and dialect are
+// handled as in WhereInSubquery; sub is copied.
+func (b *SelectBuilder) WhereExists(sub *SelectBuilder) *SelectBuilder {
+	b.where = append(b.where, existsClause{sub: sub.Clone()})
+	return b
+}
+
+// WhereNotExists adds `NOT EXISTS (<sub>)`. See WhereExists.
+func (b *SelectBuilder) WhereNotExists(sub *SelectBuilder) *SelectBuilder {
+	b.where = append(b.where, existsClause{not: true, sub: sub.Clone()})
+	return b
+}
+
+// existsClause is `[NOT] EXISTS (subquery)`.
+type existsClause struct {
+	not bool
+	sub *SelectBuilder
+}
+
+func (c existsClause) render(w *queryWriter) error {
+	if err := checkSubqueryDialect(w, c.sub); err != nil {
+		return err
+	}
+	if c.not {
+		w.write("NOT ")
+	}
+	w.write("EXISTS (")
+	if err := c.sub.render(w); err != nil {
+		return err
+	}
+	w.write(")")
+	return nil
+}
+
+// ValidateSubquery reports whether b can be used as the subquery of
+// WhereInSubquery on its dialect, returning ErrKindInvalidInput if not.
+// MySQL rejects LIMIT or OFFSET in an IN subquery at execution time
+// ("doesn't yet support 'LIMIT & IN/ALL/ANY/SOME subquery'"); wrap such a
+// query in a derived table instead, which MySQL accepts:
+//
+//	top := Select("scores", DialectMySQL).Columns("user_id").OrderBy("score", Desc).Limit(10)
+//	sub := Select("", DialectMySQL).FromSubquery(top, "t").Columns("t.user_id")
+//
+// EXISTS subqueries have no such restriction.
+func (b *SelectBuilder) ValidateSubquery() error {
+	if b.dialect == DialectMySQL && b.hasLimit() {
+		return errs.New(errs.ErrKindInvalidInput,
+			"MySQL does not support LIMIT or OFFSET in an IN subquery; select from it as a derived table instead")
+	}
+	return nil
+}
+
+// hasLimit reports whether b or one of its UNION parts sets LIMIT or
+// OFFSET. A derived table in FROM is its own scope and is not checked.
+func (b *SelectBuilder) hasLimit() bool {
+	if b.limit != nil || b.offset != nil {
+		return true
+	}
+	for _, u := range b.unions {
+		if u.query.hasLimit() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSubqueryDialect rejects a subquery built for a different dialect
+// than the query it is embedded in.
+func checkSubqueryDialect(w *queryWriter, sub *SelectBuilder) error {
+	if sub.dialect != w.dialect {
+		return errs.New(errs.ErrKindInvalidInput, "subquery dialect does not match the parent query")
+	}
+	return nil
+}
+
+// likeEscape is the LIKE escape character used by WhereContains and
+// friends. '!' rather than backslash: a backslash literal is spelled
+// differently on Postgres and MySQL, and also depends on MySQL's sql_mode.
+const likeEscape = '!'
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// escapeLike escapes LIKE metacharacters in s so it matches literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// WhereContains adds a LIKE match for column containing substring
+// literally — `%` and `_` in substring match only themselves:
+//
+//	Select("products", DialectPostgres).WhereContains("name", "50%")
+//	// SELECT * FROM "products" WHERE "name" LIKE $1 ESCAPE '!'   -- $1 = "%50!%%"
+//
+// Use it for user-supplied search terms instead of Where(col, "LIKE", …).
+func (b *SelectBuilder) WhereContains(column, substring string) *SelectBuilder {
+	b.where = append(b.where, likeClause{column: column, pattern: "%" + escapeLike(substring) + "%"})
+	return b
+}
+
+// WhereStartsWith adds a LIKE match for column starting with prefix
+// literally. See WhereContains.
+func (b *SelectBuilder) WhereStartsWith(column, prefix string) *SelectBuilder {
+	b.where = append(b.where, likeClause{column: column, pattern: escapeLike(prefix) + "%"})
+	return b
+}
+
+// WhereEndsWith adds a LIKE match for column ending with suffix literally.
+// See WhereContains.
+func (b *SelectBuilder) WhereEndsWith(column, suffix string) *SelectBuilder {
+	b.where = append(b.where, likeClause{column: column, pattern: "%" + escapeLike(suffix)})
+	return b
+}
+
+// likeClause is `column LIKE pattern ESCAPE '!'` with an escaped pattern.
+type likeClause struct {
+	column  string
+	pattern string
+}
+
+func (c likeClause) render(w *queryWriter) error {
+	col, err := w.ident(c.column)
+	if err != nil {
+		return err
+	}
+	w.write(col, " LIKE ", w.bind(c.pattern), " ESCAPE '", string(likeEscape), "'")
+	return nil
+}
+
+// WhereILike adds a case-insensitive LIKE match of column against pattern:
+//
+//	Select("users", DialectPostgres).WhereILike("email", "%@example.com")
+//	// SELECT * FROM "users" WHERE "email" ILIKE $1
+//
+// Postgres uses ILIKE; MySQL, which lacks it, gets
+// `LOWER(col) LIKE LOWER(?)`, independent of the column's collation.
+// pattern is passed through as-is, so % and _ keep their wildcard meaning.
+func (b *SelectBuilder) WhereILike(column, pattern string) *SelectBuilder {
+	b.where = append(b.where, ilikeClause{column: column, pattern: pattern})
+	return b
+}
+
+// ilikeClause is a case-insensitive `column LIKE pattern`.
+type ilikeClause struct {
+	column  string
+	pattern string
+}
+
+func (c ilikeClause) render(w *queryWriter) error {
+	col, err := w.ident(c.column)
+	if err != nil {
+		return err
+	}
+	if w.dialect == DialectPostgres {
+		w.write(col, " ILIKE ", w.bind(c.pattern))
+		return nil
+	}
+	w.write("LOWER(", col, ") LIKE LOWER(", w.bind(c.pattern), ")")
+	return nil
+}
+
+// WhereJSON adds a comparison on a field inside a JSON column. path is a
+// dot-separated list of object keys and array indexes; the field is
+// compared as text:
+//
+//	Select("events", DialectPostgres).WhereJSON("data", "user.status", "=", "active")
+//	// SELECT * FROM "events" WHERE "data"->'user'->>'status' = $1
+//
+//	Select("events", DialectMySQL).WhereJSON("data", "items.0.sku", "=", "A1")
+//	// SELECT * FROM "events" WHERE JSON_UNQUOTE(JSON_EXTRACT("data", '$.items[0].sku')) = ?
+//
+// The path is spliced into the SQL, so each key must be a plain identifier
+// ([A-Za-z_][A-Za-z0-9_]*) or a non-negative integer; anything else is
+// reported by Build. op is checked against the same allowlist as Where.
+func (b *SelectBuilder) WhereJSON(column, path, op string, value any) *SelectBuilder {
+	b.where = append(b.where, jsonClause{column: column, path: path, op: op, value: value})
+	return b
+}
+
+// jsonPathKey is an allowed JSON path key; integers are array indexes.
+var jsonPathKey = regexp.MustCompile(`^(?:[A-Za-z_][A-Za-z0-9_]*|[0-9]+)$`)
+
+// jsonClause is `column->path op value`.
+type jsonClause struct {
+	column string
+	path   string
+	op     string
+	value  any
+}
+
+func (c jsonClause) render(w *queryWriter) error {
+	op, err := checkOp(w, c.op)
+	if err != nil {
+		return err
+	}
+	keys := strings.Split(c.path, ".")
+	for _, k := range keys {
+		if !jsonPathKey.MatchString(k) {
+			return errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("WhereJSON: invalid path %q", c.path))
+		}
+	}
+	col, err := w.ident(c.column)
+	if err != nil {
+		return err
+	}
+
+	if w.dialect == DialectPostgres {
+		w.write(col)
+		for i, k := range keys {
+			arrow := "->"
+			if i == len(keys)-1 {
+				arrow = "->>"
+			}
+			if _, err := strconv.Atoi(k); err == nil {
+				w.write(arrow, k)
+			} else {
+				w.write(arrow, "'", k, "'")
+			}
+		}
+	} else {
+		w.write("JSON_UNQUOTE(JSON_EXTRACT(", col, ", '$")
+		for _, k := range keys {
+			if _, err := strconv.Atoi(k); err == nil {
+				w.write("[", k, "]")
+			} else {
+				w.write(".", k)
+			}
+		}
+		w.write("'))")
+	}
+	w.write(" ", op, " ", w.bind(c.value))
+	return nil
+}
+
+// WhereInTuple adds a row-constructor IN for composite keys, combined
+// with other conditions using AND:
+//
+//	Select("order_items", DialectPostgres).
+//	    WhereInTuple([]string{"order_id", "line"}, [][]any{{7, 1}, {7, 2}})
+//	// SELECT * FROM "order_items" WHERE ("order_id", "line") IN (($1, $2), ($3, $4))
+//
+// MySQL uses the same syntax. Every tuple must have one value per column,
+// checked at Build time. An empty rows matches no rows.
+func (b *SelectBuilder) WhereInTuple(cols []string, rows [][]any) *SelectBuilder {
+	b.where = append(b.where, tupleInClause{cols: cols, rows: rows})
+	return b
+}
+
+// tupleInClause is `(c1, c2, …) IN ((v1, v2, …), …)`.
+type tupleInClause struct {
+	cols []string
+	rows [][]any
+}
+
+func (c tupleInClause) render(w *queryWriter) error {
+	if len(c.cols) == 0 {
+		return errs.New(errs.ErrKindInvalidInput, "WhereInTuple: at least one column is required")
+	}
+	for i, row := range c.rows {
+		if len(row) != len(c.cols) {
+			return errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("WhereInTuple: tuple %d has %d value(s) for %d column(s)", i+1, len(row), len(c.cols)))
+		}
+	}
+	cols, err := identList(w, c.cols)
+	if err != nil {
+		return err
+	}
+	if len(c.rows) == 0 {
+		w.write("1 = 0")
+		return nil
+	}
+
+	w.write("(", strings.Join(cols, ", "), ") IN (")
+	for i, row := range c.rows {
+		if i > 0 {
+			w.write(", ")
+		}
+		w.write("(")
+		for j, v := range row {
+			if j > 0 {
+				w.write(", ")
+			}
+			w.write(w.bind(v))
+		}
+		w.write(")")
+	}
+	w.write(")")
+	return nil
+}
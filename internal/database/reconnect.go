@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/clock"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// defaultReconnectWindow bounds pool recreation when Config.ReconnectWindow
+// is zero.
+const defaultReconnectWindow = 30 * time.Second
+
+// reconnectDB is the DB Open returns when Config.AutoReconnect is set. When
+// an operation fails with ErrKindConnectionFailed and the pool no longer
+// answers Ping, it closes the pool and opens a new one, retrying with
+// backoff for up to window, then repeats the operation once on the new pool.
+//
+// ExecResult is never repeated, as the statement may have been applied
+// before the connection died; the pool is still recreated for the next
+// call. QueryRow reports failures only from Scan, too late to repeat.
+type reconnectDB struct {
+	open   func(ctx context.Context) (DB, error)
+	window time.Duration
+	clock  clock.Clock
+
+	mu sync.RWMutex
+	db DB
+
+	healMu sync.Mutex // one goroutine recreates the pool; others wait for it
+}
+
+func newReconnectDB(db DB, open func(ctx context.Context) (DB, error), window time.Duration) *reconnectDB {
+	if window == 0 {
+		window = defaultReconnectWindow
+	}
+	return &reconnectDB{open: open, window: window, clock: clock.Real, db: db}
+}
+
+func (r *reconnectDB) current() DB {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.db
+}
+
+// heal is called after an operation on failed reported a lost connection.
+// It returns the DB to retry on, or false if failed is still healthy (the
+// error was not a dead pool) or no new pool could be opened in time.
+func (r *reconnectDB) heal(ctx context.Context, failed DB) (DB, bool) {
+	r.healMu.Lock()
+	defer r.healMu.Unlock()
+
+	// Another caller may have replaced the pool while we waited.
+	if db := r.current(); db != failed {
+		return db, true
+	}
+	if failed.Ping(ctx) == nil {
+		return nil, false
+	}
+
+	deadline := r.clock.Now().Add(r.window)
+	backoff := 100 * time.Millisecond
+	for {
+		fresh, err := r.open(ctx)
+		if err == nil {
+			r.mu.Lock()
+			r.db = fresh
+			r.mu.Unlock()
+			// Closing a pool waits for its checked-out connections, which
+			// callers still holding Rows or a Tx may keep for a while; do
+			// it in the background so healMu is not held meanwhile.
+			go failed.Close()
+			return fresh, true
+		}
+		if r.clock.Now().Add(backoff).After(deadline) {
+			return nil, false
+		}
+
+		t := r.clock.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, false
+		case <-t.C():
+		}
+		backoff = min(backoff*2, 5*time.Second)
+	}
+}
+
+// withReconnect runs op, and once more on a recreated pool if op failed
+// because the pool's connections are gone.
+func withReconnect[T any](ctx context.Context, r *reconnectDB, op func(DB) (T, error)) (T, error) {
+	db := r.current()
+	v, err := op(db)
+	if !errs.IsConnectionFailed(err) {
+		return v, err
+	}
+	if fresh, ok := r.heal(ctx, db); ok {
+		return op(fresh)
+	}
+	return v, err
+}
+
+func (r *reconnectDB) Ping(ctx context.Context) error {
+	_, err := withReconnect(ctx, r, func(db DB) (struct{}, error) {
+		return struct{}{}, db.Ping(ctx)
+	})
+	return err
+}
+
+func (r *reconnectDB) Probe(ctx context.Context) error {
+	_, err := withReconnect(ctx, r, func(db DB) (struct{}, error) {
+		return struct{}{}, db.Probe(ctx)
+	})
+	return err
+}
+
+func (r *reconnectDB) Close() {
+	r.current().Close()
+}
+
+func (r *reconnectDB) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	return withReconnect(ctx, r, func(db DB) (Rows, error) {
+		return db.Query(ctx, sql, args...)
+	})
+}
+
+func (r *reconnectDB) QueryRow(ctx context.Context, sql string, args ...any) (Row, error) {
+	return withReconnect(ctx, r, func(db DB) (Row, error) {
+		return db.QueryRow(ctx, sql, args...)
+	})
+}
+
+func (r *reconnectDB) ExecResult(ctx context.Context, sql string, args ...any) (*Result, error) {
+	db := r.current()
+	res, err := db.ExecResult(ctx, sql, args...)
+	if errs.IsConnectionFailed(err) {
+		r.heal(ctx, db)
+	}
+	return res, err
+}
+
+func (r *reconnectDB) ListTables(ctx context.Context) ([]string, error) {
+	return withReconnect(ctx, r, func(db DB) ([]string, error) {
+		return db.ListTables(ctx)
+	})
+}
+
+func (r *reconnectDB) TableExists(ctx context.Context, table string) (bool, error) {
+	return withReconnect(ctx, r, func(db DB) (bool, error) {
+		return db.TableExists(ctx, table)
+	})
+}
+
+func (r *reconnectDB) InspectSchema(ctx context.Context) (*Schema, error) {
+	return withReconnect(ctx, r, func(db DB) (*Schema, error) {
+		return db.InspectSchema(ctx)
+	})
+}
+
+func (r *reconnectDB) InspectSchemaWith(ctx context.Context, opts InspectOptions) (*Schema, error) {
+	return withReconnect(ctx, r, func(db DB) (*Schema, error) {
+		return db.InspectSchemaWith(ctx, opts)
+	})
+}
+
+func (r *reconnectDB) InspectTable(ctx context.Context, table string) (*TableInfo, error) {
+	return withReconnect(ctx, r, func(db DB) (*TableInfo, error) {
+		return db.InspectTable(ctx, table)
+	})
+}
+
+func (r *reconnectDB) Begin(ctx context.Context) (Tx, error) {
+	return withReconnect(ctx, r, func(db DB) (Tx, error) {
+		return db.Begin(ctx)
+	})
+}
+
+func (r *reconnectDB) BeginTx(ctx context.Context, opts TxOptions) (Tx, error) {
+	return withReconnect(ctx, r, func(db DB) (Tx, error) {
+		return db.BeginTx(ctx, opts)
+	})
+}
+
+func (r *reconnectDB) Dialect() Dialect {
+	return r.current().Dialect()
+}
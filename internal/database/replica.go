@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/clock"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// replicaCooldown is how long a replica is skipped after it fails a Ping or
+// a query with a connection error.
+const replicaCooldown = 30 * time.Second
+
+// ReplicaSet is a DB that spreads reads across read replicas and keeps
+// everything else on the primary.
+//
+// Read methods (Query, QueryRow, ListTables, TableExists and the Inspect
+// methods) are routed round-robin to healthy replicas; transactions
+// (Begin) always run on the primary. A replica that fails a Ping or
+// returns ErrKindConnectionFailed is skipped for replicaCooldown; when no
+// replica is healthy, reads fall back to the primary.
+//
+// It is safe for concurrent use by multiple goroutines.
+type ReplicaSet struct {
+	primary  DB
+	replicas []*replica
+	next     atomic.Uint64
+	clock    clock.Clock
+}
+
+type replica struct {
+	db        DB
+	downUntil atomic.Int64 // unix nanos; 0 means healthy
+}
+
+// NewReplicaSet returns a DB that sends reads to replicas and everything
+// else to primary. With no replicas, every call goes to primary.
+func NewReplicaSet(primary DB, replicas ...DB) DB {
+	rs := &ReplicaSet{primary: primary, clock: clock.Real}
+	for _, r := range replicas {
+		rs.replicas = append(rs.replicas, &replica{db: r})
+	}
+	return rs
+}
+
+// Ping pings the primary and every replica. Replicas that fail are marked
+// down; only a primary failure is returned to the caller.
+func (rs *ReplicaSet) Ping(ctx context.Context) error {
+	for _, r := range rs.replicas {
+		if err := r.db.Ping(ctx); err != nil {
+			r.markDown(rs.clock.Now())
+		} else {
+			r.downUntil.Store(0)
+		}
+	}
+	return rs.primary.Ping(ctx)
+}
+
+// Probe probes the primary and every replica. As with Ping, replicas that
+// fail are marked down — so a replica that accepts connections but cannot
+// serve reads stops receiving them — and only a primary failure is
+// returned.
+func (rs *ReplicaSet) Probe(ctx context.Context) error {
+	for _, r := range rs.replicas {
+		if err := r.db.Probe(ctx); err != nil {
+			r.markDown(rs.clock.Now())
+		} else {
+			r.downUntil.Store(0)
+		}
+	}
+	return rs.primary.Probe(ctx)
+}
+
+// Close closes the primary and all replicas.
+func (rs *ReplicaSet) Close() {
+	for _, r := range rs.replicas {
+		r.db.Close()
+	}
+	rs.primary.Close()
+}
+
+// Query runs on a healthy replica, retrying once on the primary if the
+// replica turns out to be unreachable.
+func (rs *ReplicaSet) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	r := rs.pick()
+	if r == nil {
+		return rs.primary.Query(ctx, sql, args...)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if errs.IsConnectionFailed(err) {
+		r.markDown(rs.clock.Now())
+		return rs.primary.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+// QueryRow runs on a healthy replica. Errors from QueryRow surface on Scan,
+// so there is no transparent fallback to the primary here.
+func (rs *ReplicaSet) QueryRow(ctx context.Context, sql string, args ...any) (Row, error) {
+	return rs.reader().QueryRow(ctx, sql, args...)
+}
+
+// ExecResult always runs on the primary.
+func (rs *ReplicaSet) ExecResult(ctx context.Context, sql string, args ...any) (*Result, error) {
+	return rs.primary.ExecResult(ctx, sql, args...)
+}
+
+func (rs *ReplicaSet) ListTables(ctx context.Context) ([]string, error) {
+	return rs.reader().ListTables(ctx)
+}
+
+func (rs *ReplicaSet) TableExists(ctx context.Context, table string) (bool, error) {
+	return rs.reader().TableExists(ctx, table)
+}
+
+func (rs *ReplicaSet) InspectSchema(ctx context.Context) (*Schema, error) {
+	return rs.reader().InspectSchema(ctx)
+}
+
+func (rs *ReplicaSet) InspectSchemaWith(ctx context.Context, opts InspectOptions) (*Schema, error) {
+	return rs.reader().InspectSchemaWith(ctx, opts)
+}
+
+func (rs *ReplicaSet) InspectTable(ctx context.Context, table string) (*TableInfo, error) {
+	return rs.reader().InspectTable(ctx, table)
+}
+
+// Begin always starts the transaction on the primary.
+func (rs *ReplicaSet) Begin(ctx context.Context) (Tx, error) {
+	return rs.primary.Begin(ctx)
+}
+
+// BeginTx always starts the transaction on the primary, even when
+// opts.ReadOnly is set, so reads inside it see the primary's state.
+func (rs *ReplicaSet) BeginTx(ctx context.Context, opts TxOptions) (Tx, error) {
+	return rs.primary.BeginTx(ctx, opts)
+}
+
+// Dialect returns the primary's dialect; replicas are assumed to match.
+func (rs *ReplicaSet) Dialect() Dialect {
+	return rs.primary.Dialect()
+}
+
+// reader returns the DB that should serve the next read.
+func (rs *ReplicaSet) reader() DB {
+	if r := rs.pick(); r != nil {
+		return r.db
+	}
+	return rs.primary
+}
+
+// pick returns the next healthy replica in round-robin order, or nil if
+// none are healthy.
+func (rs *ReplicaSet) pick() *replica {
+	n := len(rs.replicas)
+	if n == 0 {
+		return nil
+	}
+
+	now := rs.clock.Now().UnixNano()
+	start := rs.next.Add(1)
+	for i := 0; i < n; i++ {
+		r := rs.replicas[(start+uint64(i))%uint64(n)]
+		if r.downUntil.Load() <= now {
+			return r
+		}
+	}
+	return nil
+}
+
+func (r *replica) markDown(now time.Time) {
+	r.downUntil.Store(now.Add(replicaCooldown).UnixNano())
+}
@@ -1,19 +1,61 @@
 package database
 
-import "github.com/koustreak/DatRi/internal/errs"
+import (
+	"fmt"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
 
 // ScanRows reads all rows from the result set and returns them as a slice
 // of maps, where each key is the column name and each value is the Go-native
-// representation of the DB value.
+// representation of the DB value. Postgres integer arrays become []int64 and
+// text arrays []string; UUIDs and decimals become strings, and types with a
+// handler from RegisterScanner are converted by it.
 //
 // The returned slice is always non-nil (empty slice on zero rows).
 // ScanRows always closes the Rows — callers do not need to call Close().
 func ScanRows(rows Rows) ([]map[string]any, error) {
+	return ScanRowsWith(rows, ScanOptions{})
+}
+
+// ScanOptions controls how ScanRowsWith converts database values.
+type ScanOptions struct {
+	// Typed normalises values using each column's database type so results
+	// are identical across engines: time.Time for dates/timestamps, string
+	// for decimals (no float precision loss), bool for booleans, int64 for
+	// integers, float64 for floats and string for text. Types it does not
+	// recognise are left as the driver returned them.
+	Typed bool
+
+	// DecodeJSON unmarshals json/jsonb columns into Go values
+	// (map[string]any, []any, string, float64, bool or nil) instead of
+	// returning the raw bytes. It is independent of Typed.
+	DecodeJSON bool
+
+	// OmitNulls leaves NULL columns out of the row map entirely, for sparse
+	// JSON responses. By default every column is present and NULL is nil,
+	// so a missing key always means the column was not selected.
+	OmitNulls bool
+}
+
+// ScanRowsWith is ScanRows with options. It always closes the Rows.
+func ScanRowsWith(rows Rows, opts ScanOptions) ([]map[string]any, error) {
 	defer rows.Close()
 
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, errs.Wrap(errs.ErrKindQueryFailed, "failed to read column names", err)
+		return nil, wrapError("failed to read column names", err)
+	}
+
+	// Column types are always read: Postgres array columns are normalised
+	// even without opts.Typed.
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, wrapError("failed to read column types", err)
+	}
+	dbTypes := make([]string, len(types))
+	for i, t := range types {
+		dbTypes[i] = t.DatabaseType
 	}
 
 	result := make([]map[string]any, 0)
@@ -26,23 +68,84 @@ func ScanRows(rows Rows) ([]map[string]any, error) {
 		}
 
 		if err := rows.Scan(destPtrs...); err != nil {
-			return nil, errs.Wrap(errs.ErrKindQueryFailed, "failed to scan row", err)
+			return nil, wrapError("failed to scan row", err)
 		}
 
 		row := make(map[string]any, len(columns))
 		for i, col := range columns {
-			row[col] = dest[i]
+			v := dest[i]
+			if opts.DecodeJSON && isJSONType(dbTypes[i]) {
+				var decoded any
+				if err := ScanJSON(v, &decoded); err != nil {
+					return nil, wrapError(fmt.Sprintf("failed to decode JSON column %q", col), err)
+				}
+				v = decoded
+			} else if fn := scannerFor(dbTypes[i]); fn != nil && v != nil {
+				if v, err = fn(v); err != nil {
+					return nil, wrapError(fmt.Sprintf("failed to convert column %q", col), err)
+				}
+			} else if opts.Typed {
+				if v, err = normalizeValue(v, dbTypes[i]); err != nil {
+					return nil, wrapError(fmt.Sprintf("failed to convert column %q", col), err)
+				}
+			} else if isArrayType(dbTypes[i]) {
+				if v, err = normalizeArray(v, dbTypes[i]); err != nil {
+					return nil, wrapError(fmt.Sprintf("failed to convert column %q", col), err)
+				}
+			}
+			if v == nil && opts.OmitNulls {
+				continue
+			}
+			row[col] = v
 		}
 		result = append(result, row)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errs.Wrap(errs.ErrKindQueryFailed, "error during row iteration", err)
+		return nil, wrapError("error during row iteration", err)
 	}
 
 	return result, nil
 }
 
+// ScanRowsReuse streams rows to fn without building a map per row, for
+// large or wide result sets where ScanRows' allocations dominate.
+//
+// Reuse contract: row is a single buffer that is overwritten by the next
+// row, and cols is shared across calls. fn must not retain either slice
+// after it returns — copy the values it needs (e.g. append(nil, row...)).
+// The values inside row are not reused, so keeping an individual element
+// is safe.
+//
+// Values are returned as the driver produced them; no ScanOptions apply.
+// ScanRowsReuse always closes the Rows.
+func ScanRowsReuse(rows Rows, fn func(row []any, cols []string)) error {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return wrapError("failed to read column names", err)
+	}
+
+	dest := make([]any, len(columns))
+	destPtrs := make([]any, len(columns))
+	for i := range dest {
+		destPtrs[i] = &dest[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(destPtrs...); err != nil {
+			return wrapError("failed to scan row", err)
+		}
+		fn(dest, columns)
+	}
+
+	if err := rows.Err(); err != nil {
+		return wrapError("error during row iteration", err)
+	}
+	return nil
+}
+
 // ScanRow reads a single row and returns it as a map.
 func ScanRow(row Row, columns []string) (map[string]any, error) {
 	dest := make([]any, len(columns))
@@ -52,7 +155,7 @@ func ScanRow(row Row, columns []string) (map[string]any, error) {
 	}
 
 	if err := row.Scan(destPtrs...); err != nil {
-		return nil, errs.Wrap(errs.ErrKindQueryFailed, "failed to scan single row", err)
+		return nil, wrapError("failed to scan single row", err)
 	}
 
 	result := make(map[string]any, len(columns))
@@ -61,3 +164,16 @@ func ScanRow(row Row, columns []string) (map[string]any, error) {
 	}
 	return result, nil
 }
+
+// wrapError wraps err with msg, keeping the ErrKind the driver already
+// assigned (e.g. ErrKindTimeout on cancellation, ErrKindNotFound on no rows).
+// Errors without a kind are classified as ErrKindQueryFailed.
+func wrapError(msg string, err error) *errs.Error {
+	kind := errs.KindOf(err)
+	if kind == errs.ErrKindUnknown {
+		kind = errs.ErrKindQueryFailed
+	}
+	e := errs.Wrap(kind, msg, err)
+	e.Code = errs.Code(err)
+	return e
+}
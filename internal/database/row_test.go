@@ -0,0 +1,76 @@
+package database_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/database/dbtest"
+)
+
+// wideResult returns the columns and rows of a result set wide enough for
+// per-row map allocation to dominate ScanRows.
+func wideResult(numCols, numRows int) ([]string, [][]any) {
+	columns := make([]string, numCols)
+	for i := range columns {
+		columns[i] = fmt.Sprintf("col_%d", i)
+	}
+	rows := make([][]any, numRows)
+	for r := range rows {
+		row := make([]any, numCols)
+		for c := range row {
+			if c%2 == 0 {
+				row[c] = int64(r*numCols + c)
+			} else {
+				row[c] = "value"
+			}
+		}
+		rows[r] = row
+	}
+	return columns, rows
+}
+
+func BenchmarkScanRows(b *testing.B) {
+	columns, data := wideResult(50, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := database.ScanRows(dbtest.NewRows(columns, data...))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(result) != len(data) {
+			b.Fatalf("got %d rows, want %d", len(result), len(data))
+		}
+	}
+}
+
+func BenchmarkScanRowsReuse(b *testing.B) {
+	columns, data := wideResult(50, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		err := database.ScanRowsReuse(dbtest.NewRows(columns, data...), func(row []any, cols []string) {
+			n++
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if n != len(data) {
+			b.Fatalf("got %d rows, want %d", n, len(data))
+		}
+	}
+}
+
+func TestScanRowsWithTypedBit(t *testing.T) {
+	rows := dbtest.NewRows([]string{"on", "off"}, []any{[]byte{0x1}, []byte{0x0}}).
+		WithColumnTypes("BIT", "BIT")
+	got, err := database.ScanRowsWith(rows, database.ScanOptions{Typed: true})
+	if err != nil {
+		t.Fatalf("ScanRowsWith: %v", err)
+	}
+	if got[0]["on"] != true || got[0]["off"] != false {
+		t.Errorf("BIT(1) values = %#v, %#v, want true, false", got[0]["on"], got[0]["off"])
+	}
+}
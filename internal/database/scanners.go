@@ -0,0 +1,68 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ScanFunc converts a non-NULL value read from a column into the Go value
+// returned to callers. raw is whatever the driver produced for the column.
+type ScanFunc func(raw any) (any, error)
+
+var (
+	scannersMu sync.RWMutex
+	scanners   = map[string]ScanFunc{}
+)
+
+func init() {
+	RegisterScanner("UUID", scanUUID)
+	RegisterScanner("NUMERIC", toDecimalString)
+	RegisterScanner("DECIMAL", toDecimalString)
+}
+
+// RegisterScanner makes ScanRows, ScanRowsWith, ScanStructs and the export
+// writers convert values of columns whose ColumnType.DatabaseType is
+// dbType (matched case-insensitively) with fn, whatever the ScanOptions:
+//
+//	database.RegisterScanner("GEOMETRY", func(raw any) (any, error) {
+//	    return geo.ParseWKB(raw.([]byte))
+//	})
+//
+// NULL is never passed to fn. A handler replaces the built-in conversion
+// for its type; registering a type again replaces the previous handler.
+// Built-in handlers turn UUID into its canonical string and NUMERIC and
+// DECIMAL into decimal strings. Register handlers during initialisation,
+// before any scanning. RegisterScanner panics if fn is nil.
+func RegisterScanner(dbType string, fn ScanFunc) {
+	if fn == nil {
+		panic("database: RegisterScanner func is nil")
+	}
+	scannersMu.Lock()
+	defer scannersMu.Unlock()
+	scanners[strings.ToUpper(dbType)] = fn
+}
+
+// scannerFor returns the handler registered for dbType, or nil.
+func scannerFor(dbType string) ScanFunc {
+	scannersMu.RLock()
+	defer scannersMu.RUnlock()
+	return scanners[strings.ToUpper(dbType)]
+}
+
+// scanUUID formats a UUID as 8-4-4-4-12 hex. pgx returns the 16 raw bytes.
+func scanUUID(raw any) (any, error) {
+	switch u := raw.(type) {
+	case [16]byte:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+	case []byte:
+		if len(u) == 16 {
+			return scanUUID([16]byte(u))
+		}
+		return string(u), nil
+	case string:
+		return u, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %T to UUID", raw)
+	}
+}
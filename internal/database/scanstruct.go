@@ -0,0 +1,322 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// ScanStructs reads all rows into dest, which must point to a slice of
+// structs or struct pointers:
+//
+//	type User struct {
+//	    ID        int64          `db:"id"`
+//	    Email     string         `db:"email"`
+//	    Nickname  sql.NullString `db:"nickname"`
+//	    DeletedAt *time.Time     `db:"deleted_at"`
+//	}
+//	var users []User
+//	err := ScanStructs(rows, &users)
+//
+// A column fills the field tagged `db:"column"`, or else the exported
+// field whose name matches the column ignoring case and underscores
+// (CreatedAt ← created_at). Fields tagged `db:"-"` are skipped, as are
+// columns without a field.
+//
+// Fields implementing sql.Scanner (sql.NullString, sql.NullTime, custom
+// types) are handed to the driver unchanged. Other fields receive the
+// value normalised as with ScanOptions{Typed: true} and converted to the
+// field's type; NULL needs a pointer field and leaves it nil. Decimals
+// fill float and integer fields too, and a value that would overflow its
+// field, or lose a fraction in an integer one, is an error.
+//
+// ScanStructs always closes the Rows.
+func ScanStructs(rows Rows, dest any) error {
+	defer rows.Close()
+
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Pointer || slice.Elem().Kind() != reflect.Slice {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("ScanStructs: dest must be a pointer to a slice, got %T", dest))
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	structType, isPtr := elemType, false
+	if elemType.Kind() == reflect.Pointer {
+		structType, isPtr = elemType.Elem(), true
+	}
+	if structType.Kind() != reflect.Struct {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("ScanStructs: dest must hold structs, got %s", elemType))
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return wrapError("failed to read column names", err)
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return wrapError("failed to read column types", err)
+	}
+	fields := structFields(structType, columns)
+
+	for rows.Next() {
+		item := reflect.New(structType).Elem()
+		raw := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i, f := range fields {
+			switch {
+			case f == nil:
+				ptrs[i] = new(any)
+			case f.scanner:
+				ptrs[i] = item.FieldByIndex(f.index).Addr().Interface()
+			default:
+				ptrs[i] = &raw[i]
+			}
+		}
+
+		if err := rows.Scan(ptrs...); err != nil {
+			return wrapError("failed to scan row", err)
+		}
+
+		for i, f := range fields {
+			if f == nil || f.scanner {
+				continue
+			}
+			v, err := normalizeValue(raw[i], types[i].DatabaseType)
+			if err == nil {
+				err = assignField(item.FieldByIndex(f.index), v)
+			}
+			if err != nil {
+				return wrapError(fmt.Sprintf("failed to convert column %q", columns[i]), err)
+			}
+		}
+
+		if isPtr {
+			item = item.Addr()
+		}
+		slice.Set(reflect.Append(slice, item))
+	}
+
+	if err := rows.Err(); err != nil {
+		return wrapError("error during row iteration", err)
+	}
+	return nil
+}
+
+// structField is the destination of one column.
+type structField struct {
+	index   []int
+	scanner bool // *field implements sql.Scanner
+}
+
+// structFields returns the field for each column, nil where none matches.
+func structFields(t reflect.Type, columns []string) []*structField {
+	byName := make(map[string]*structField)
+	byFold := make(map[string]*structField)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		sf := &structField{index: f.Index, scanner: reflect.PointerTo(f.Type).Implements(scannerType)}
+		switch tag := f.Tag.Get("db"); tag {
+		case "-":
+		case "":
+			byFold[foldName(f.Name)] = sf
+		default:
+			byName[tag] = sf
+		}
+	}
+
+	fields := make([]*structField, len(columns))
+	for i, col := range columns {
+		if f, ok := byName[col]; ok {
+			fields[i] = f
+		} else {
+			fields[i] = byFold[foldName(col)]
+		}
+	}
+	return fields
+}
+
+// foldName lowercases s and drops underscores, so CreatedAt and
+// created_at compare equal.
+func foldName(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
+
+// assignField stores a normalised value v into field, allocating pointer
+// fields, converting between numeric types and between string and []byte,
+// and parsing decimal strings (how NUMERIC/DECIMAL arrive) into numeric
+// fields. A value that does not fit the field is an error, never
+// truncated.
+func assignField(field reflect.Value, v any) error {
+	if v == nil {
+		if field.Kind() != reflect.Pointer {
+			return fmt.Errorf("NULL cannot be stored in a field of type %s; use a pointer or sql.Null type", field.Type())
+		}
+		field.SetZero()
+		return nil
+	}
+	if field.Kind() == reflect.Pointer {
+		p := reflect.New(field.Type().Elem())
+		if err := assignField(p.Elem(), v); err != nil {
+			return err
+		}
+		field.Set(p)
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Type().AssignableTo(field.Type()):
+		field.Set(rv)
+	case isNumeric(rv.Kind()) && isNumeric(field.Kind()):
+		return setNumeric(field, rv)
+	case rv.Kind() == reflect.String && isNumeric(field.Kind()):
+		return parseNumeric(field, rv.String())
+	case rv.Type() == bytesType && isNumeric(field.Kind()):
+		return parseNumeric(field, string(rv.Bytes()))
+	case rv.Kind() == reflect.String && field.Type() == bytesType,
+		rv.Type() == bytesType && field.Kind() == reflect.String:
+		field.Set(rv.Convert(field.Type()))
+	default:
+		return fmt.Errorf("cannot store %T in a field of type %s", v, field.Type())
+	}
+	return nil
+}
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// setNumeric stores the numeric rv into the numeric field, failing when the
+// value overflows the field or a float has a fraction an integer field
+// cannot hold.
+func setNumeric(field, rv reflect.Value) error {
+	overflow := func() error {
+		return fmt.Errorf("value %v overflows a field of type %s", rv, field.Type())
+	}
+	switch {
+	case isFloat(field.Kind()):
+		var f float64
+		switch {
+		case isFloat(rv.Kind()):
+			f = rv.Float()
+		case isInt(rv.Kind()):
+			f = float64(rv.Int())
+		default:
+			f = float64(rv.Uint())
+		}
+		if field.OverflowFloat(f) {
+			return overflow()
+		}
+		field.SetFloat(f)
+
+	case isInt(field.Kind()):
+		var i int64
+		switch {
+		case isInt(rv.Kind()):
+			i = rv.Int()
+		case isFloat(rv.Kind()):
+			f := rv.Float()
+			if f != math.Trunc(f) {
+				return fmt.Errorf("value %v has a fraction a field of type %s cannot hold", rv, field.Type())
+			}
+			if f < math.MinInt64 || f >= math.MaxInt64 {
+				return overflow()
+			}
+			i = int64(f)
+		default:
+			u := rv.Uint()
+			if u > math.MaxInt64 {
+				return overflow()
+			}
+			i = int64(u)
+		}
+		if field.OverflowInt(i) {
+			return overflow()
+		}
+		field.SetInt(i)
+
+	default: // unsigned field
+		var u uint64
+		switch {
+		case isInt(rv.Kind()):
+			if rv.Int() < 0 {
+				return overflow()
+			}
+			u = uint64(rv.Int())
+		case isFloat(rv.Kind()):
+			f := rv.Float()
+			if f != math.Trunc(f) {
+				return fmt.Errorf("value %v has a fraction a field of type %s cannot hold", rv, field.Type())
+			}
+			if f < 0 || f >= math.MaxUint64 {
+				return overflow()
+			}
+			u = uint64(f)
+		default:
+			u = rv.Uint()
+		}
+		if field.OverflowUint(u) {
+			return overflow()
+		}
+		field.SetUint(u)
+	}
+	return nil
+}
+
+// parseNumeric parses the decimal string s into the numeric field. Integer
+// fields accept a decimal whose fraction is all zeros ("12.00").
+func parseNumeric(field reflect.Value, s string) error {
+	bits := field.Type().Bits()
+	switch {
+	case isFloat(field.Kind()):
+		f, err := strconv.ParseFloat(s, bits)
+		if err != nil {
+			return fmt.Errorf("cannot store %q in a field of type %s: %w", s, field.Type(), err)
+		}
+		field.SetFloat(f)
+	case isInt(field.Kind()):
+		i, err := strconv.ParseInt(trimZeroFraction(s), 10, bits)
+		if err != nil {
+			return fmt.Errorf("cannot store %q in a field of type %s: %w", s, field.Type(), err)
+		}
+		field.SetInt(i)
+	default:
+		u, err := strconv.ParseUint(trimZeroFraction(s), 10, bits)
+		if err != nil {
+			return fmt.Errorf("cannot store %q in a field of type %s: %w", s, field.Type(), err)
+		}
+		field.SetUint(u)
+	}
+	return nil
+}
+
+// trimZeroFraction drops a fraction made only of zeros, so "12.00" parses
+// as an integer while "12.50" still fails.
+func trimZeroFraction(s string) string {
+	whole, frac, ok := strings.Cut(s, ".")
+	if ok && strings.Trim(frac, "0") == "" {
+		return whole
+	}
+	return s
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNumeric(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
@@ -0,0 +1,55 @@
+package database_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/database/dbtest"
+)
+
+func TestScanStructsDecimalIntoNumericFields(t *testing.T) {
+	type product struct {
+		Price  float64 `db:"price"`
+		Stock  int64   `db:"stock"`
+		Rating *float32
+	}
+	rows := dbtest.NewRows([]string{"price", "stock", "rating"},
+		[]any{[]byte("19.99"), "42.00", "4.5"}).
+		WithColumnTypes("DECIMAL", "NUMERIC", "NUMERIC")
+
+	var got []product
+	if err := database.ScanStructs(rows, &got); err != nil {
+		t.Fatalf("ScanStructs: %v", err)
+	}
+	if got[0].Price != 19.99 || got[0].Stock != 42 || got[0].Rating == nil || *got[0].Rating != 4.5 {
+		t.Errorf("ScanStructs = %+v", got[0])
+	}
+}
+
+func TestScanStructsRejectsLossyValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		dbType  string
+		value   any
+		dest    any
+		wantErr string
+	}{
+		{"int64 overflows int8", "INT8", int64(300), &[]struct{ V int8 }{}, "overflows"},
+		{"negative into uint", "INT8", int64(-1), &[]struct{ V uint32 }{}, "overflows"},
+		{"float fraction into int", "FLOAT8", 1.5, &[]struct{ V int64 }{}, "fraction"},
+		{"float overflows float32", "FLOAT8", 1e300, &[]struct{ V float32 }{}, "overflows"},
+		{"decimal fraction into int", "NUMERIC", "12.50", &[]struct{ V int64 }{}, "cannot store"},
+		{"decimal overflows int16", "NUMERIC", "70000", &[]struct{ V int16 }{}, "cannot store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := dbtest.NewRows([]string{"v"}, []any{tt.value}).WithColumnTypes(tt.dbType)
+			err := database.ScanStructs(rows, tt.dest)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ScanStructs error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
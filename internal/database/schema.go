@@ -4,54 +4,100 @@ package database
 // It is built once at startup and cached — never fetched per-request.
 type Schema struct {
 	// Tables maps table name to its metadata.
-	Tables map[string]*TableInfo
+	Tables map[string]*TableInfo `json:"tables"`
 }
 
 // TableInfo describes a single table.
 type TableInfo struct {
 	// Name is the table name as it appears in the database.
-	Name string
+	Name string `json:"name"`
 
 	// Columns is the ordered list of columns (by ordinal position).
-	Columns []*ColumnInfo
+	Columns []*ColumnInfo `json:"columns"`
 
 	// PrimaryKey holds the column names that form the primary key.
 	// Composite PKs are fully supported.
-	PrimaryKey []string
+	PrimaryKey []string `json:"primary_key"`
 
 	// ForeignKeys lists all outbound foreign key relationships.
-	ForeignKeys []*ForeignKey
+	ForeignKeys []*ForeignKey `json:"foreign_keys"`
+
+	// Indexes lists the table's indexes, including the one backing the
+	// primary key, ordered by name.
+	Indexes []*Index `json:"indexes"`
+
+	// Comment is the table's description, or nil when it has none.
+	Comment *string `json:"comment,omitempty"`
 }
 
 // ColumnInfo describes a single column within a table.
 type ColumnInfo struct {
 	// Name is the column name.
-	Name string
+	Name string `json:"name"`
 
 	// DataType is the database-level type (e.g. "integer", "text", "timestamp").
-	DataType string
+	DataType string `json:"data_type"`
 
 	// Nullable reports whether the column accepts NULL values.
-	Nullable bool
+	Nullable bool `json:"nullable"`
 
 	// IsPrimary reports whether this column is part of the primary key.
-	IsPrimary bool
+	IsPrimary bool `json:"is_primary"`
 
 	// IsUnique reports whether this column has a UNIQUE constraint.
-	IsUnique bool
+	IsUnique bool `json:"is_unique"`
 
 	// Default is the column's default expression, if any (e.g. "now()", "0").
-	Default *string
+	Default *string `json:"default,omitempty"`
+
+	// Comment is the column's description, or nil when it has none.
+	Comment *string `json:"comment,omitempty"`
+
+	// Collation and CharSet are the effective collation and character set
+	// of a text column (e.g. "utf8mb4_0900_ai_ci"/"utf8mb4", "en_US.UTF-8"/
+	// "UTF8"). Both are nil for non-text columns.
+	Collation *string `json:"collation,omitempty"`
+	CharSet   *string `json:"charset,omitempty"`
+
+	// NumericPrecision and NumericScale describe numeric columns, e.g. 10
+	// and 2 for DECIMAL(10,2). Both are nil for non-numeric columns, and
+	// for a NUMERIC declared without a precision. Integer and float
+	// columns report the engine's own precision (binary digits on Postgres).
+	NumericPrecision *int `json:"numeric_precision,omitempty"`
+	NumericScale     *int `json:"numeric_scale,omitempty"`
 }
 
-// ForeignKey describes a single foreign key relationship on a column.
+// ForeignKey describes a single foreign key constraint. Composite keys are
+// one ForeignKey with several columns, paired by position with RefColumns.
 type ForeignKey struct {
-	// Column is the local column that holds the foreign key.
-	Column string
+	// Name is the constraint name.
+	Name string `json:"name"`
+
+	// Columns are the local columns that hold the foreign key, in key order.
+	Columns []string `json:"columns"`
 
 	// RefTable is the referenced table.
-	RefTable string
+	RefTable string `json:"ref_table"`
+
+	// RefColumns are the referenced columns in RefTable; RefColumns[i] is
+	// referenced by Columns[i].
+	RefColumns []string `json:"ref_columns"`
+
+	// OnDelete and OnUpdate are the referential actions: "CASCADE",
+	// "RESTRICT", "SET NULL", "SET DEFAULT" or "NO ACTION".
+	OnDelete string `json:"on_delete"`
+	OnUpdate string `json:"on_update"`
+}
+
+// Index describes a single index on a table.
+type Index struct {
+	// Name is the index name ("PRIMARY" for the MySQL primary key).
+	Name string `json:"name"`
+
+	// Columns holds the indexed columns in key order. Expression parts of
+	// an index appear as "" so positions stay true to the key.
+	Columns []string `json:"columns"`
 
-	// RefColumn is the referenced column in the RefTable.
-	RefColumn string
+	// Unique reports whether the index enforces uniqueness.
+	Unique bool `json:"unique"`
 }
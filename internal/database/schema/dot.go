@@ -0,0 +1,85 @@
+package schema
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// ExportDOT renders s as an entity-relationship diagram in Graphviz DOT
+// format. Each table is a node listing its columns, with primary key and
+// unique columns marked; each foreign key is an edge from the referencing
+// table to the referenced one, labelled "columns → ref_columns".
+//
+// Render it with e.g. `dot -Tsvg schema.dot -o schema.svg`. Output is
+// deterministic, so it can be committed alongside the JSON export.
+func ExportDOT(s *database.Schema) (string, error) {
+	if s == nil {
+		return "", errs.New(errs.ErrKindInvalidInput, "schema is nil")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph schema {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=plaintext, fontname=\"Helvetica\"];\n")
+	sb.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	names := tableNames(s)
+	for _, name := range names {
+		writeDOTNode(&sb, s.Tables[name])
+	}
+
+	for _, name := range names {
+		fks := append([]*database.ForeignKey(nil), s.Tables[name].ForeignKeys...)
+		sort.SliceStable(fks, func(i, j int) bool { return fks[i].Name < fks[j].Name })
+		for _, fk := range fks {
+			label := strings.Join(fk.Columns, ", ") + " → " + strings.Join(fk.RefColumns, ", ")
+			fmt.Fprintf(&sb, "  %s -> %s [label=%s];\n", dotID(name), dotID(fk.RefTable), dotID(label))
+		}
+	}
+
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// writeDOTNode writes t as an HTML-like table label: a header row with the
+// table name, then one row per column.
+func writeDOTNode(sb *strings.Builder, t *database.TableInfo) {
+	fmt.Fprintf(sb, "  %s [label=<\n", dotID(t.Name))
+	sb.WriteString("    <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n")
+	fmt.Fprintf(sb, "      <tr><td bgcolor=\"lightgrey\" colspan=\"2\"><b>%s</b></td></tr>\n", html.EscapeString(t.Name))
+	for _, col := range t.Columns {
+		name := html.EscapeString(col.Name)
+		switch {
+		case col.IsPrimary:
+			name = "<u>" + name + "</u> (PK)"
+		case col.IsUnique:
+			name += " (UQ)"
+		}
+		fmt.Fprintf(sb, "      <tr><td align=\"left\">%s</td><td align=\"left\">%s</td></tr>\n",
+			name, html.EscapeString(col.DataType))
+	}
+	sb.WriteString("    </table>\n  >];\n")
+}
+
+// dotID quotes s as a DOT identifier.
+func dotID(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// tableNames returns the names of s's tables in sorted order, skipping nil
+// entries.
+func tableNames(s *database.Schema) []string {
+	names := make([]string, 0, len(s.Tables))
+	for name, t := range s.Tables {
+		if t != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
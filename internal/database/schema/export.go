@@ -0,0 +1,73 @@
+// Package schema works with an introspected *database.Schema after the
+// fact: serialising it for version control and documentation, and linting
+// it for common design problems. Nothing here talks to a database.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// ExportJSON serialises s as indented JSON suitable for committing to
+// version control. The output is stable across runs: tables are sorted by
+// name and foreign keys by constraint name, while columns and primary key columns
+// keep their ordinal order, which is part of the schema. s is not modified.
+func ExportJSON(s *database.Schema) ([]byte, error) {
+	if s == nil {
+		return nil, errs.New(errs.ErrKindInvalidInput, "schema is nil")
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	// encoding/json writes map keys in sorted order, so only slices whose
+	// order the database does not guarantee need sorting.
+	if err := enc.Encode(normalized(s)); err != nil {
+		return nil, errs.Wrap(errs.ErrKindInvalidInput, "failed to encode schema", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportJSON parses a schema previously written by ExportJSON.
+func ImportJSON(data []byte) (*database.Schema, error) {
+	var s database.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errs.Wrap(errs.ErrKindInvalidInput, "failed to decode schema", err)
+	}
+	if s.Tables == nil {
+		s.Tables = make(map[string]*database.TableInfo)
+	}
+	for name, t := range s.Tables {
+		if t == nil {
+			return nil, errs.New(errs.ErrKindInvalidInput, fmt.Sprintf("schema table %q is null", name))
+		}
+		if t.Name == "" {
+			t.Name = name
+		}
+	}
+	return &s, nil
+}
+
+// normalized returns a shallow copy of s with foreign keys in a
+// deterministic order.
+func normalized(s *database.Schema) *database.Schema {
+	out := &database.Schema{Tables: make(map[string]*database.TableInfo, len(s.Tables))}
+	for name, t := range s.Tables {
+		if t == nil {
+			continue
+		}
+		cp := *t
+		cp.ForeignKeys = append([]*database.ForeignKey(nil), t.ForeignKeys...)
+		sort.SliceStable(cp.ForeignKeys, func(i, j int) bool {
+			return cp.ForeignKeys[i].Name < cp.ForeignKeys[j].Name
+		})
+		out.Tables[name] = &cp
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"sort"
+
+	"github.com/koustreak/DatRi/internal/database"
+)
+
+// TablesWithoutPrimaryKey returns, in sorted order, the tables in s that
+// have no primary key column. Such tables break row-based replication and
+// most ORMs, and cannot be served by GET /{table}/{id}.
+func TablesWithoutPrimaryKey(s *database.Schema) []string {
+	if s == nil {
+		return nil
+	}
+
+	var out []string
+	for _, name := range tableNames(s) {
+		if !hasPrimaryKey(s.Tables[name]) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func hasPrimaryKey(t *database.TableInfo) bool {
+	if len(t.PrimaryKey) > 0 {
+		return true
+	}
+	for _, col := range t.Columns {
+		if col.IsPrimary {
+			return true
+		}
+	}
+	return false
+}
+
+// ForeignKey is a foreign key together with the table that declares it.
+type ForeignKey struct {
+	Table string
+	*database.ForeignKey
+}
+
+// UnindexedForeignKeys returns the foreign keys in s that no index on
+// their table supports, sorted by table and constraint name. An index
+// supports a key when its leading columns are the key's columns, in any
+// order. Without one, every delete or key update on the referenced table
+// scans the referencing one, and joins along the key cannot use an index.
+func UnindexedForeignKeys(s *database.Schema) []ForeignKey {
+	if s == nil {
+		return nil
+	}
+
+	var out []ForeignKey
+	for _, name := range tableNames(s) {
+		t := s.Tables[name]
+		fks := append([]*database.ForeignKey(nil), t.ForeignKeys...)
+		sort.SliceStable(fks, func(i, j int) bool { return fks[i].Name < fks[j].Name })
+		for _, fk := range fks {
+			if !indexed(t.Indexes, fk.Columns) {
+				out = append(out, ForeignKey{Table: name, ForeignKey: fk})
+			}
+		}
+	}
+	return out
+}
+
+// indexed reports whether some index starts with exactly the given columns.
+// Expression key parts ("" in Index.Columns) never match a column.
+func indexed(indexes []*database.Index, columns []string) bool {
+	want := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		want[c] = true
+	}
+	for _, idx := range indexes {
+		if len(idx.Columns) < len(columns) {
+			continue
+		}
+		covered := true
+		for _, c := range idx.Columns[:len(columns)] {
+			if c == "" || !want[c] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
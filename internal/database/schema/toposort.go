@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/database"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// TopoSort returns the tables in s ordered so that every table comes after
+// the tables its foreign keys reference: insert in this order, delete in
+// reverse. Ties are broken by name, so the result is deterministic.
+//
+// Foreign keys to tables outside s are ignored. A cycle, including a table
+// referencing itself, has no valid order and is reported as an
+// ErrKindInvalidInput error naming the tables involved.
+func TopoSort(s *database.Schema) ([]string, error) {
+	if s == nil {
+		return nil, errs.New(errs.ErrKindInvalidInput, "schema is nil")
+	}
+
+	names := tableNames(s)
+	parents := make(map[string]map[string]bool, len(names)) // child → referenced tables
+	children := make(map[string][]string, len(names))       // parent → referencing tables
+	for _, name := range names {
+		parents[name] = make(map[string]bool)
+	}
+	for _, name := range names {
+		for _, fk := range s.Tables[name].ForeignKeys {
+			if _, ok := parents[fk.RefTable]; !ok || parents[name][fk.RefTable] {
+				continue
+			}
+			parents[name][fk.RefTable] = true
+			children[fk.RefTable] = append(children[fk.RefTable], name)
+		}
+	}
+
+	pending := make(map[string]int, len(names)) // table → unsorted parents
+	var ready []string
+	for _, name := range names {
+		pending[name] = len(parents[name])
+		if pending[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := make([]string, 0, len(names))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, child := range children[name] {
+			pending[child]--
+			if pending[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+
+	if len(order) < len(names) {
+		cycle := findCycle(names, parents, pending)
+		return nil, errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("foreign keys form a cycle: %s", strings.Join(cycle, " → ")))
+	}
+	return order, nil
+}
+
+// findCycle returns one cycle among the tables left unsorted, as a path
+// that starts and ends at the same table. Every unsorted table references
+// another unsorted table, so following references must revisit one.
+func findCycle(names []string, parents map[string]map[string]bool, pending map[string]int) []string {
+	var start string
+	for _, name := range names {
+		if pending[name] > 0 {
+			start = name
+			break
+		}
+	}
+
+	var path []string
+	seen := make(map[string]int)
+	for cur := start; ; {
+		if i, ok := seen[cur]; ok {
+			return append(path[i:], cur)
+		}
+		seen[cur] = len(path)
+		path = append(path, cur)
+
+		next := make([]string, 0, len(parents[cur]))
+		for p := range parents[cur] {
+			if pending[p] > 0 {
+				next = append(next, p)
+			}
+		}
+		sort.Strings(next)
+		cur = next[0]
+	}
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// ExecScript runs a script of semicolon-separated statements, in order,
+// inside one transaction: if any statement fails, the script is rolled
+// back and the error names the failing statement's position.
+//
+//	err := ExecScript(ctx, db, `
+//	    CREATE TABLE tags (id serial PRIMARY KEY, name text NOT NULL);
+//	    INSERT INTO tags (name) VALUES ('go'), ('sql');
+//	`)
+//
+// Statements are split client-side (see SplitStatements), so the MySQL
+// driver does not need multiStatements enabled. MySQL commits implicitly
+// around DDL such as CREATE TABLE, so there a failed script can leave
+// earlier DDL statements applied; Postgres rolls back DDL too.
+func ExecScript(ctx context.Context, db DB, script string) error {
+	stmts := SplitStatements(script, db.Dialect())
+	if len(stmts) == 0 {
+		return nil
+	}
+
+	return InTx(ctx, db, TxOptions{}, func(tx Tx) error {
+		for i, stmt := range stmts {
+			if _, err := tx.ExecResult(ctx, stmt); err != nil {
+				return errs.Wrap(errs.KindOf(err),
+					fmt.Sprintf("script statement %d of %d failed", i+1, len(stmts)), err)
+			}
+		}
+		return nil
+	})
+}
+
+// SplitStatements splits script into statements at top-level semicolons,
+// dropping surrounding whitespace and statements that hold nothing but
+// whitespace and comments. Semicolons inside
+// quoted strings and identifiers ('…', "…", `…`) and comments (--, /* */)
+// do not split, nor do they inside dollar-quoted bodies ($$…$$,
+// $tag$…$tag$) on Postgres or # comments on MySQL. Backslash escapes in
+// strings are honoured on MySQL only. Comments are kept as part of the
+// statement they appear in.
+func SplitStatements(script string, d Dialect) []string {
+	var stmts []string
+	start, code := 0, false
+	flush := func(end int) {
+		if code {
+			stmts = append(stmts, strings.TrimSpace(script[start:end]))
+		}
+		code = false
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == ';':
+			flush(i)
+			i++
+			start = i
+		case c == '\'' || c == '"' || c == '`':
+			i, code = skipQuoted(script, i, d == DialectMySQL), true
+		case c == '-' && strings.HasPrefix(script[i:], "--"), c == '#' && d == DialectMySQL:
+			i = skipPast(script, i, "\n")
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = skipPast(script, i+2, "*/")
+		case c == '$' && d == DialectPostgres:
+			i, code = skipDollarQuoted(script, i), true
+		default:
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				code = true
+			}
+			i++
+		}
+	}
+	flush(len(script))
+	return stmts
+}
+
+// skipQuoted returns the index just past the quoted section opened at i.
+// A doubled quote is an escaped quote; with backslashes set, a backslash
+// in '…' or "…" escapes the next byte too.
+func skipQuoted(s string, i int, backslashes bool) int {
+	q := s[i]
+	for i++; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && backslashes && q != '`':
+			i++
+		case s[i] == q:
+			if i+1 < len(s) && s[i+1] == q {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// skipPast returns the index just past the next end at or after i, or
+// len(s) if there is none.
+func skipPast(s string, i int, end string) int {
+	if j := strings.Index(s[i:], end); j >= 0 {
+		return i + j + len(end)
+	}
+	return len(s)
+}
+
+// skipDollarQuoted returns the index just past a dollar-quoted body
+// opened at i, or i+1 if the $ does not open one (e.g. a $1 placeholder).
+func skipDollarQuoted(s string, i int) int {
+	j := i + 1
+	for j < len(s) && (s[j] == '_' || isAlnum(s[j])) {
+		j++
+	}
+	if j >= len(s) || s[j] != '$' || (j > i+1 && s[i+1] >= '0' && s[i+1] <= '9') {
+		return i + 1
+	}
+	tag := s[i : j+1]
+	return skipPast(s, j+1, tag)
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/errs"
+	"github.com/koustreak/DatRi/internal/logger"
+)
+
+// WithSlowLog returns a DB that logs a warning for every query that takes
+// longer than threshold, including queries run inside transactions.
+//
+// A query's duration runs from the call until its Rows are closed (or, for
+// QueryRow, until Scan returns), so time spent streaming a large result
+// counts. Each entry carries the SQL, the duration, the number of args —
+// never their values, which may hold personal data — and the ErrKind if
+// the query failed. All other methods pass straight through to db.
+func WithSlowLog(db DB, threshold time.Duration, log *logger.Logger) DB {
+	return &slowLogDB{DB: db, sl: &slowLogger{threshold: threshold, log: log}}
+}
+
+type slowLogDB struct {
+	DB
+	sl *slowLogger
+}
+
+func (d *slowLogDB) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	return d.sl.query(d.DB.Query, ctx, sql, args)
+}
+
+func (d *slowLogDB) QueryRow(ctx context.Context, sql string, args ...any) (Row, error) {
+	return d.sl.queryRow(d.DB.QueryRow, ctx, sql, args)
+}
+
+func (d *slowLogDB) ExecResult(ctx context.Context, sql string, args ...any) (*Result, error) {
+	return d.sl.exec(d.DB.ExecResult, ctx, sql, args)
+}
+
+func (d *slowLogDB) Begin(ctx context.Context) (Tx, error) {
+	tx, err := d.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &slowLogTx{Tx: tx, sl: d.sl}, nil
+}
+
+func (d *slowLogDB) BeginTx(ctx context.Context, opts TxOptions) (Tx, error) {
+	tx, err := d.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &slowLogTx{Tx: tx, sl: d.sl}, nil
+}
+
+type slowLogTx struct {
+	Tx
+	sl *slowLogger
+}
+
+func (t *slowLogTx) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	return t.sl.query(t.Tx.Query, ctx, sql, args)
+}
+
+func (t *slowLogTx) QueryRow(ctx context.Context, sql string, args ...any) (Row, error) {
+	return t.sl.queryRow(t.Tx.QueryRow, ctx, sql, args)
+}
+
+func (t *slowLogTx) ExecResult(ctx context.Context, sql string, args ...any) (*Result, error) {
+	return t.sl.exec(t.Tx.ExecResult, ctx, sql, args)
+}
+
+// slowLogger holds the settings shared by a slowLogDB and its transactions.
+type slowLogger struct {
+	threshold time.Duration
+	log       *logger.Logger
+}
+
+type queryFunc func(ctx context.Context, sql string, args ...any) (Rows, error)
+type queryRowFunc func(ctx context.Context, sql string, args ...any) (Row, error)
+type execFunc func(ctx context.Context, sql string, args ...any) (*Result, error)
+
+func (s *slowLogger) query(fn queryFunc, ctx context.Context, sql string, args []any) (Rows, error) {
+	start := time.Now()
+	rows, err := fn(ctx, sql, args...)
+	if err != nil {
+		s.observe(sql, len(args), time.Since(start), err)
+		return nil, err
+	}
+	return &slowLogRows{Rows: rows, s: s, sql: sql, nargs: len(args), start: start}, nil
+}
+
+func (s *slowLogger) queryRow(fn queryRowFunc, ctx context.Context, sql string, args []any) (Row, error) {
+	start := time.Now()
+	row, err := fn(ctx, sql, args...)
+	if err != nil {
+		s.observe(sql, len(args), time.Since(start), err)
+		return nil, err
+	}
+	return &slowLogRow{Row: row, s: s, sql: sql, nargs: len(args), start: start}, nil
+}
+
+func (s *slowLogger) exec(fn execFunc, ctx context.Context, sql string, args []any) (*Result, error) {
+	start := time.Now()
+	res, err := fn(ctx, sql, args...)
+	s.observe(sql, len(args), time.Since(start), err)
+	return res, err
+}
+
+// observe logs the query if it exceeded the threshold.
+func (s *slowLogger) observe(sql string, nargs int, elapsed time.Duration, err error) {
+	if elapsed < s.threshold {
+		return
+	}
+	fields := map[string]interface{}{
+		"sql":         sql,
+		"args":        nargs,
+		"duration_ms": elapsed.Milliseconds(),
+	}
+	if err != nil {
+		fields["err_kind"] = errs.KindOf(err).String()
+	}
+	s.log.LogWith("warn", "slow query", fields)
+}
+
+// slowLogRows reports the query once, when the caller closes the rows.
+type slowLogRows struct {
+	Rows
+	s      *slowLogger
+	sql    string
+	nargs  int
+	start  time.Time
+	closed bool
+}
+
+func (r *slowLogRows) Close() {
+	r.Rows.Close()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.s.observe(r.sql, r.nargs, time.Since(r.start), r.Rows.Err())
+}
+
+type slowLogRow struct {
+	Row
+	s     *slowLogger
+	sql   string
+	nargs int
+	start time.Time
+}
+
+func (r *slowLogRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	if errs.IsNotFound(err) {
+		r.s.observe(r.sql, r.nargs, time.Since(r.start), nil)
+	} else {
+		r.s.observe(r.sql, r.nargs, time.Since(r.start), err)
+	}
+	return err
+}
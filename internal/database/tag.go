@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+type queryTagKey struct{}
+
+// maxQueryTagLen bounds the comment added to every tagged statement.
+const maxQueryTagLen = 128
+
+// WithQueryTag returns a context whose queries are tagged with tag. The
+// drivers prepend it to each statement as a SQL comment (/* tag */ SELECT …),
+// so it appears in pg_stat_activity, the MySQL processlist and slow query
+// logs, letting DBAs trace a query back to the code path that issued it.
+//
+// The tag is sanitised (see sanitizeQueryTag); a tag that sanitises to
+// nothing leaves queries untouched.
+func WithQueryTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, queryTagKey{}, sanitizeQueryTag(tag))
+}
+
+// QueryTag returns the sanitised tag set by WithQueryTag, or "".
+func QueryTag(ctx context.Context) string {
+	tag, _ := ctx.Value(queryTagKey{}).(string)
+	return tag
+}
+
+// TagQuery prepends ctx's query tag to sql as a comment. Drivers call it
+// on every statement they send; without a tag sql is returned unchanged.
+func TagQuery(ctx context.Context, sql string) string {
+	tag := QueryTag(ctx)
+	if tag == "" {
+		return sql
+	}
+	return "/* " + tag + " */ " + sql
+}
+
+// sanitizeQueryTag keeps only characters that cannot end or nest a comment
+// or form a MySQL executable comment (/*! … */) or optimizer hint (/*+ … */):
+// letters, digits, space and _ - . : = , @. Everything else is dropped and
+// the result is truncated to maxQueryTagLen bytes.
+func sanitizeQueryTag(tag string) string {
+	var sb strings.Builder
+	for _, r := range tag {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			strings.ContainsRune(" _-.:=,@", r):
+			if sb.Len() >= maxQueryTagLen {
+				return strings.TrimSpace(sb.String())
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
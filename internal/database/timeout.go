@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// QueryTimeout is db.Query with its own deadline, for a statement that
+// needs more or less time than the caller's context allows:
+//
+//	rows, err := QueryTimeout(ctx, db, 30*time.Second, "SELECT * FROM report")
+//
+// The deadline covers reading the result too, and is released when the
+// Rows are closed. A query that runs out of time fails with
+// ErrKindTimeout, from Query or from Rows.Err. ctx's own deadline still
+// applies if it is earlier.
+func QueryTimeout(ctx context.Context, db DB, timeout time.Duration, sql string, args ...any) (Rows, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, timeoutError(ctx, timeout, err)
+	}
+	return &timeoutRows{Rows: rows, ctx: ctx, cancel: cancel, timeout: timeout}, nil
+}
+
+// QueryRowTimeout is db.QueryRow with its own deadline; see QueryTimeout.
+// The deadline is released when Scan returns.
+func QueryRowTimeout(ctx context.Context, db DB, timeout time.Duration, sql string, args ...any) (Row, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	row, err := db.QueryRow(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, timeoutError(ctx, timeout, err)
+	}
+	return &timeoutRow{Row: row, ctx: ctx, cancel: cancel, timeout: timeout}, nil
+}
+
+// timeoutError reports err as ErrKindTimeout when it happened because
+// ctx's deadline passed; drivers do not always classify it that way
+// when the deadline interrupts reading a result.
+func timeoutError(ctx context.Context, timeout time.Duration, err error) error {
+	if err == nil || errs.IsTimeout(err) || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return errs.Wrap(errs.ErrKindTimeout, fmt.Sprintf("query exceeded its %s timeout", timeout), err)
+}
+
+// timeoutRows releases the query's deadline when closed.
+type timeoutRows struct {
+	Rows
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+func (r *timeoutRows) Scan(dest ...any) error {
+	return timeoutError(r.ctx, r.timeout, r.Rows.Scan(dest...))
+}
+
+func (r *timeoutRows) Err() error {
+	return timeoutError(r.ctx, r.timeout, r.Rows.Err())
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// timeoutRow releases the query's deadline once scanned.
+type timeoutRow struct {
+	Row
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+func (r *timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return timeoutError(r.ctx, r.timeout, r.Row.Scan(dest...))
+}
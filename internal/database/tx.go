@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// IsolationLevel is the transaction isolation level requested via TxOptions.
+type IsolationLevel int
+
+const (
+	// IsolationDefault leaves the isolation level to the server default
+	// (READ COMMITTED on Postgres, REPEATABLE READ on MySQL).
+	IsolationDefault IsolationLevel = iota
+	IsolationReadCommitted
+	IsolationRepeatableRead
+	IsolationSerializable
+)
+
+// String returns the SQL name of the level, or "DEFAULT".
+func (l IsolationLevel) String() string {
+	switch l {
+	case IsolationReadCommitted:
+		return "READ COMMITTED"
+	case IsolationRepeatableRead:
+		return "REPEATABLE READ"
+	case IsolationSerializable:
+		return "SERIALIZABLE"
+	default:
+		return "DEFAULT"
+	}
+}
+
+// TxOptions configures a transaction started with BeginTx.
+// The zero value uses the server's default isolation level, read-write.
+//
+// Serializable transactions can fail with a serialization error (SQLSTATE
+// 40001 on Postgres) and should be retried by the caller; see InTxRetry.
+type TxOptions struct {
+	IsolationLevel IsolationLevel
+	ReadOnly       bool
+}
+
+// InTx runs fn in a transaction started with opts, committing if fn
+// returns nil and rolling back otherwise:
+//
+//	err := InTx(ctx, db, TxOptions{IsolationLevel: IsolationRepeatableRead, ReadOnly: true},
+//	    func(tx Tx) error {
+//	        // … consistent reads through tx …
+//	        return nil
+//	    })
+//
+// fn's error is returned unchanged; a failed rollback does not hide it. If
+// fn panics, the transaction is rolled back and the panic re-raised. fn
+// must not call Commit or Rollback itself.
+func InTx(ctx context.Context, db DB, opts TxOptions, fn func(tx Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			// Rollback errors are dropped: fn's error or panic is the cause,
+			// and the server discards the transaction with the connection.
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	committed = true
+	return tx.Commit(ctx)
+}
+
+// InTxRetry is InTx for transactions that may lose a conflict with a
+// concurrent one: if fn or Commit fails with a serialization failure or a
+// deadlock (see IsSerializationFailure), the transaction is rolled back
+// and fn run again in a new one, up to maxAttempts runs in total:
+//
+//	err := InTxRetry(ctx, db, TxOptions{IsolationLevel: IsolationSerializable}, 5,
+//	    func(tx Tx) error {
+//	        // … read and write through tx …
+//	        return nil
+//	    })
+//
+// Retries back off from 10ms, doubling up to 1s, with jitter so the
+// conflicting transactions do not collide again. fn must be safe to repeat:
+// it may only act through tx and must reset any state it builds up. The
+// last error is returned when attempts run out or ctx is done.
+func InTxRetry(ctx context.Context, db DB, opts TxOptions, maxAttempts int, fn func(tx Tx) error) error {
+	backoff := 10 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := InTx(ctx, db, opts, fn)
+		if err == nil || attempt >= maxAttempts || !IsSerializationFailure(err) || ctx.Err() != nil {
+			return err
+		}
+
+		t := time.NewTimer(backoff/2 + rand.N(backoff/2+1))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		backoff = min(backoff*2, time.Second)
+	}
+}
+
+// IsSerializationFailure reports whether err aborted a transaction that
+// can succeed when retried: a serialization failure (SQLSTATE 40001) or a
+// deadlock (Postgres 40P01, MySQL 1213).
+func IsSerializationFailure(err error) bool {
+	switch errs.Code(err) {
+	case "40001", "40P01", "1213":
+		return true
+	}
+	return false
+}
+
+// savepointName is the allowlist for savepoint names. Savepoint names
+// cannot be bound as parameters, so they are restricted to plain
+// identifiers before being written into SQL.
+var savepointName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+
+// ValidateSavepointName returns an ErrKindInvalidInput error unless name is
+// a plain identifier (letters, digits and underscores, not starting with a
+// digit, at most 63 characters).
+func ValidateSavepointName(name string) error {
+	if !savepointName.MatchString(name) {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("invalid savepoint name %q: must be a plain identifier", name))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// GetString returns row[col] as a string. Text that the driver returned as
+// []byte (MySQL) is converted; other types are a mismatch.
+//
+// GetString, GetInt64, GetTime and GetBool read rows produced by ScanRows
+// without per-driver type assertions:
+//
+//	rows, err := ScanRows(result)
+//	for _, r := range rows {
+//	    id, err := GetInt64(r, "id")
+//	    created, err := GetTime(r, "created_at")
+//	}
+//
+// They return an ErrKindInvalidInput error when col is missing from the
+// row, is NULL, or holds a value that cannot be converted.
+func GetString(row map[string]any, col string) (string, error) {
+	return getAs[string](row, col, "string", func(v any) (any, error) {
+		switch s := v.(type) {
+		case string:
+			return s, nil
+		case []byte:
+			return string(s), nil
+		default:
+			return nil, fmt.Errorf("cannot convert %T to string", v)
+		}
+	})
+}
+
+// GetInt64 returns row[col] as an int64, widening any integer type and
+// parsing integers returned as text. Unsigned values above math.MaxInt64
+// and floats are a mismatch. See GetString.
+func GetInt64(row map[string]any, col string) (int64, error) {
+	return getAs[int64](row, col, "int64", func(v any) (any, error) {
+		switch n := v.(type) {
+		case uint8:
+			return int64(n), nil
+		case uint16:
+			return int64(n), nil
+		case uint64:
+			if n > math.MaxInt64 {
+				return nil, fmt.Errorf("%d overflows int64", n)
+			}
+			return int64(n), nil
+		default:
+			return toInt64(v)
+		}
+	})
+}
+
+// GetTime returns row[col] as a time.Time, parsing MySQL DATETIME, DATE
+// and TIMESTAMP values returned as text. See GetString.
+func GetTime(row map[string]any, col string) (time.Time, error) {
+	return getAs[time.Time](row, col, "time.Time", toTime)
+}
+
+// GetBool returns row[col] as a bool, accepting MySQL's TINYINT(1) and
+// BIT(1) representations as well as native booleans. See GetString.
+func GetBool(row map[string]any, col string) (bool, error) {
+	return getAs[bool](row, col, "bool", func(v any) (any, error) {
+		switch n := v.(type) {
+		case int32:
+			return n != 0, nil
+		case int8:
+			return n != 0, nil
+		default:
+			return toBool(v)
+		}
+	})
+}
+
+// getAs looks up col and converts its value with conv to T.
+func getAs[T any](row map[string]any, col, typeName string, conv func(any) (any, error)) (T, error) {
+	var zero T
+	v, ok := row[col]
+	if !ok {
+		return zero, errs.New(errs.ErrKindInvalidInput, fmt.Sprintf("column %q is not in the row", col))
+	}
+	if v == nil {
+		return zero, errs.New(errs.ErrKindInvalidInput, fmt.Sprintf("column %q is NULL", col))
+	}
+	out, err := conv(v)
+	if err != nil {
+		return zero, errs.Wrap(errs.ErrKindInvalidInput, fmt.Sprintf("column %q is not a %s", col, typeName), err)
+	}
+	return out.(T), nil
+}
@@ -33,6 +33,7 @@ const (
 	ErrKindQueryFailed              // SQL or storage operation error
 	ErrKindInvalidInput             // bad arguments from the caller
 	ErrKindPermissionDenied         // access denied / auth failure
+	ErrKindConflict                 // write violates a constraint (unique, FK, check, …)
 )
 
 func (k ErrKind) String() string {
@@ -49,6 +50,8 @@ func (k ErrKind) String() string {
 		return "invalid_input"
 	case ErrKindPermissionDenied:
 		return "permission_denied"
+	case ErrKindConflict:
+		return "conflict"
 	default:
 		return "unknown"
 	}
@@ -60,6 +63,10 @@ type Error struct {
 	Kind    ErrKind
 	Message string
 	Cause   error // original driver-level error, preserved for logging
+
+	// Code is the backend's native error code when there is one: a
+	// Postgres SQLSTATE ("23505") or a MySQL error number ("1062").
+	Code string
 }
 
 func (e *Error) Error() string {
@@ -86,42 +93,62 @@ func Wrap(kind ErrKind, msg string, cause error) *Error {
 	return &Error{Kind: kind, Message: msg, Cause: cause}
 }
 
+// Code returns the native backend code of the first *Error in err's chain
+// that has one, or "" if there is none.
+func Code(err error) string {
+	var e *Error
+	for errors.As(err, &e) {
+		if e.Code != "" {
+			return e.Code
+		}
+		err = e.Cause
+	}
+	return ""
+}
+
 // --- Predicates ---
 
 // IsNotFound reports whether err represents a "not found" result
 // (no rows, missing object, unknown table/bucket, …).
 func IsNotFound(err error) bool {
-	return kindOf(err) == ErrKindNotFound
+	return KindOf(err) == ErrKindNotFound
 }
 
 // IsTimeout reports whether err was caused by a deadline or context cancellation.
 func IsTimeout(err error) bool {
-	return kindOf(err) == ErrKindTimeout
+	return KindOf(err) == ErrKindTimeout
 }
 
 // IsConnectionFailed reports whether err is a connectivity or auth failure.
 func IsConnectionFailed(err error) bool {
-	return kindOf(err) == ErrKindConnectionFailed
+	return KindOf(err) == ErrKindConnectionFailed
 }
 
 // IsQueryFailed reports whether err is a backend operation failure
 // (SQL execution error, storage I/O error, …).
 func IsQueryFailed(err error) bool {
-	return kindOf(err) == ErrKindQueryFailed
+	return KindOf(err) == ErrKindQueryFailed
 }
 
 // IsInvalidInput reports whether err was caused by bad input from the caller.
 func IsInvalidInput(err error) bool {
-	return kindOf(err) == ErrKindInvalidInput
+	return KindOf(err) == ErrKindInvalidInput
 }
 
 // IsPermissionDenied reports whether err is an access control failure.
 func IsPermissionDenied(err error) bool {
-	return kindOf(err) == ErrKindPermissionDenied
+	return KindOf(err) == ErrKindPermissionDenied
+}
+
+// IsConflict reports whether err is a write rejected by a constraint
+// (duplicate key, foreign key, NOT NULL, CHECK, exclusion, …).
+func IsConflict(err error) bool {
+	return KindOf(err) == ErrKindConflict
 }
 
-// kindOf extracts the ErrKind from any error in the chain.
-func kindOf(err error) ErrKind {
+// KindOf extracts the ErrKind from any error in the chain.
+// Returns ErrKindUnknown if err is not (and does not wrap) an *Error.
+func KindOf(err error) ErrKind {
 	var e *Error
 	if errors.As(err, &e) {
 		return e.Kind
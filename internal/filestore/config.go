@@ -1,10 +1,43 @@
 package filestore
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
 // Provider identifies the file storage backend.
 type Provider string
 
 const (
 	ProviderMinIO Provider = "minio"
+	ProviderGCS   Provider = "gcs"
+)
+
+// CredentialSource selects where a driver gets its credentials.
+type CredentialSource string
+
+const (
+	// CredentialsStatic uses the credentials in Config: AccessKey and
+	// SecretKey for MinIO, the CredentialsFile service-account key for GCS
+	// (Application Default Credentials when it is empty). It is the
+	// default.
+	CredentialsStatic CredentialSource = "static"
+
+	// CredentialsEnv reads the standard environment variables:
+	// AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY (or MINIO_ROOT_USER /
+	// MINIO_ROOT_PASSWORD) for MinIO, GOOGLE_APPLICATION_CREDENTIALS for GCS.
+	CredentialsEnv CredentialSource = "env"
+
+	// CredentialsIAMRole uses the identity of the machine the process runs
+	// on: the EC2 instance profile, ECS task role or EKS service-account
+	// role for MinIO/S3, the metadata server for GCS.
+	CredentialsIAMRole CredentialSource = "iam_role"
+
+	// CredentialsProfile reads Profile from a shared AWS credentials file
+	// (CredentialsFile, default ~/.aws/credentials). MinIO only.
+	CredentialsProfile CredentialSource = "profile"
 )
 
 // Config holds all settings needed to connect to a file storage backend.
@@ -16,12 +49,21 @@ type Config struct {
 	// Example: "localhost:9000" for local MinIO.
 	Endpoint string
 
+	// Credentials selects where credentials come from. Empty means
+	// CredentialsStatic.
+	Credentials CredentialSource
+
 	// AccessKey is the access key ID (MinIO / S3 style).
+	// CredentialsStatic only.
 	AccessKey string
 
-	// SecretKey is the secret access key.
+	// SecretKey is the secret access key. CredentialsStatic only.
 	SecretKey string
 
+	// Profile is the profile to read with CredentialsProfile. Empty uses
+	// $AWS_PROFILE, else "default".
+	Profile string
+
 	// UseSSL controls whether TLS is used for the connection.
 	UseSSL bool
 
@@ -29,6 +71,16 @@ type Config struct {
 	// Leave empty for MinIO.
 	Region string
 
+	// ProjectID is the Google Cloud project whose buckets ListBuckets
+	// returns. GCS only.
+	ProjectID string
+
+	// CredentialsFile is, for GCS with CredentialsStatic, the path to a
+	// service-account key JSON file; when empty, GCS uses Application
+	// Default Credentials. For MinIO with CredentialsProfile it is the
+	// shared credentials file to read.
+	CredentialsFile string
+
 	// DefaultBucket is an optional default bucket name.
 	// Callers may override it per-request.
 	DefaultBucket string
@@ -44,3 +96,67 @@ func DefaultConfig(endpoint, accessKey, secretKey string) *Config {
 		UseSSL:    false,
 	}
 }
+
+// Validate returns an ErrKindInvalidInput error describing the first
+// setting that is missing, unsupported by the provider, or inconsistent
+// with the credential source. Drivers call it before connecting.
+func (c *Config) Validate() error {
+	src := c.credentialSource()
+	switch src {
+	case CredentialsStatic, CredentialsEnv, CredentialsIAMRole, CredentialsProfile:
+	default:
+		return invalidConfig("Credentials %q is not supported (allowed: static, env, iam_role, profile)", c.Credentials)
+	}
+	if src != CredentialsStatic && (c.AccessKey != "" || c.SecretKey != "") {
+		return invalidConfig("AccessKey and SecretKey are only used with static credentials, not %q", src)
+	}
+	if src != CredentialsProfile && c.Profile != "" {
+		return invalidConfig("Profile is only used with profile credentials, not %q", src)
+	}
+
+	switch c.Provider {
+	case ProviderMinIO:
+		if strings.TrimSpace(c.Endpoint) == "" {
+			return invalidConfig("Endpoint is required for %s", c.Provider)
+		}
+		if src == CredentialsStatic && (c.AccessKey == "" || c.SecretKey == "") {
+			return invalidConfig("AccessKey and SecretKey are required for static %s credentials", c.Provider)
+		}
+		if src != CredentialsProfile && c.CredentialsFile != "" {
+			return invalidConfig("CredentialsFile is only used with profile credentials for %s", c.Provider)
+		}
+	case ProviderGCS:
+		if c.Endpoint != "" {
+			return invalidConfig("Endpoint is not supported for %s", c.Provider)
+		}
+		if c.AccessKey != "" || c.SecretKey != "" {
+			return invalidConfig("AccessKey and SecretKey are not supported for %s; use CredentialsFile", c.Provider)
+		}
+		if src == CredentialsProfile {
+			return invalidConfig("profile credentials are not supported for %s", c.Provider)
+		}
+		if src != CredentialsStatic && c.CredentialsFile != "" {
+			return invalidConfig("CredentialsFile is only used with static credentials for %s", c.Provider)
+		}
+		if c.ProjectID == "" && c.DefaultBucket == "" {
+			return invalidConfig("ProjectID or DefaultBucket is required for %s", c.Provider)
+		}
+	case "":
+		return invalidConfig("Provider is required")
+	default:
+		return invalidConfig("Provider %q is not supported", c.Provider)
+	}
+	return nil
+}
+
+// credentialSource returns c.Credentials, defaulting to CredentialsStatic.
+func (c *Config) credentialSource() CredentialSource {
+	if c.Credentials == "" {
+		return CredentialsStatic
+	}
+	return c.Credentials
+}
+
+func invalidConfig(format string, args ...any) error {
+	return errs.New(errs.ErrKindInvalidInput, "invalid filestore config: "+fmt.Sprintf(format, args...))
+}
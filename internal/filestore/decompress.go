@@ -0,0 +1,102 @@
+package filestore
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// GetObjectDecompressed opens an object like Store.GetObject and, if it is
+// gzip- or zstd-compressed, transparently decompresses it as it is read.
+//
+// Compression is detected from ObjectInfo.ContentEncoding ("gzip",
+// "x-gzip", "zstd") or, failing that, from a ContentType of
+// application/gzip or application/zstd. Other objects are returned as-is.
+//
+// Info() still describes the stored object, so Info().Size is the
+// compressed size. Closing the returned Object closes the underlying one.
+func GetObjectDecompressed(ctx context.Context, store Store, bucket, key string) (Object, error) {
+	obj, err := store.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch compressionOf(obj.Info()) {
+	case "gzip":
+		zr, err := gzip.NewReader(obj)
+		if err != nil {
+			obj.Close()
+			return nil, errs.Wrap(errs.ErrKindQueryFailed,
+				fmt.Sprintf("object %s/%s is not valid gzip", bucket, key), err)
+		}
+		return &decompressedObject{Object: obj, r: zr, closeFn: zr.Close}, nil
+
+	case "zstd":
+		zr, err := zstd.NewReader(obj)
+		if err != nil {
+			obj.Close()
+			return nil, errs.Wrap(errs.ErrKindQueryFailed,
+				fmt.Sprintf("object %s/%s is not valid zstd", bucket, key), err)
+		}
+		return &decompressedObject{Object: obj, r: zr, closeFn: func() error {
+			zr.Close()
+			return nil
+		}}, nil
+
+	default:
+		return obj, nil
+	}
+}
+
+// compressionOf returns "gzip", "zstd" or "" for an object.
+func compressionOf(info *ObjectInfo) string {
+	switch strings.ToLower(strings.TrimSpace(info.ContentEncoding)) {
+	case "gzip", "x-gzip":
+		return "gzip"
+	case "zstd":
+		return "zstd"
+	}
+
+	ct := strings.ToLower(info.ContentType)
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	switch strings.TrimSpace(ct) {
+	case "application/gzip", "application/x-gzip":
+		return "gzip"
+	case "application/zstd":
+		return "zstd"
+	}
+	return ""
+}
+
+// decompressedObject reads through a decompressor but reports the stored
+// object's Info.
+type decompressedObject struct {
+	Object
+	r       io.Reader
+	closeFn func() error
+}
+
+func (o *decompressedObject) Read(p []byte) (int, error) {
+	n, err := o.r.Read(p)
+	if err != nil && err != io.EOF && errs.KindOf(err) == errs.ErrKindUnknown {
+		// A decoder error (corrupt data) rather than one from the store.
+		err = errs.Wrap(errs.ErrKindQueryFailed, "failed to decompress object", err)
+	}
+	return n, err
+}
+
+// Close releases the decompressor and closes the underlying object.
+func (o *decompressedObject) Close() error {
+	decErr := o.closeFn()
+	if err := o.Object.Close(); err != nil {
+		return err
+	}
+	return decErr
+}
@@ -0,0 +1,282 @@
+// Package gcs provides a Google Cloud Storage implementation of filestore.Store.
+//
+// Usage:
+//
+//	cfg := &filestore.Config{
+//	    Provider:  filestore.ProviderGCS,
+//	    ProjectID: "my-project",
+//	    // CredentialsFile: "/path/to/key.json", // default: Application Default Credentials
+//	}
+//	store, err := gcs.New(ctx, cfg)
+//	if err != nil { ... }
+//	defer store.Close()
+package gcs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/koustreak/DatRi/internal/clock"
+	"github.com/koustreak/DatRi/internal/errs"
+	"github.com/koustreak/DatRi/internal/filestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// Driver is a Google Cloud Storage implementation of filestore.Store.
+// It is safe for concurrent use by multiple goroutines.
+type Driver struct {
+	client        *storage.Client
+	projectID     string
+	defaultBucket string
+	clock         clock.Clock // for presigned-URL expiry
+}
+
+func init() {
+	filestore.Register(filestore.ProviderGCS, func(ctx context.Context, cfg *filestore.Config) (filestore.Store, error) {
+		d, err := New(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	})
+}
+
+// New connects to GCS using the provided Config and returns a Driver.
+// It authenticates with cfg.CredentialsFile when set, and with Application
+// Default Credentials otherwise. It calls Ping to validate the connection
+// before returning.
+func New(ctx context.Context, cfg *filestore.Config) (*Driver, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	// Env and IAMRole credentials are what Application Default Credentials
+	// resolve: $GOOGLE_APPLICATION_CREDENTIALS, then the metadata server.
+	var opts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrKindConnectionFailed, "failed to create gcs client", err)
+	}
+
+	d := &Driver{client: client, projectID: cfg.ProjectID, defaultBucket: cfg.DefaultBucket, clock: clock.Real}
+
+	if err := d.Ping(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// --- filestore.Store implementation ---
+
+// Ping verifies GCS is reachable: it reads the default bucket's metadata if
+// one is configured, and lists the project's buckets otherwise.
+func (d *Driver) Ping(ctx context.Context) error {
+	if d.defaultBucket != "" {
+		if _, err := d.client.Bucket(d.defaultBucket).Attrs(ctx); err != nil {
+			return mapError(err, "ping failed")
+		}
+		return nil
+	}
+	if d.projectID == "" {
+		return errs.New(errs.ErrKindInvalidInput, "gcs: ProjectID or DefaultBucket must be set")
+	}
+	if _, err := d.client.Buckets(ctx, d.projectID).Next(); err != nil && err != iterator.Done {
+		return mapError(err, "ping failed")
+	}
+	return nil
+}
+
+// Close releases the client's connections.
+func (d *Driver) Close() error {
+	return d.client.Close()
+}
+
+// ListBuckets returns all buckets in the configured project.
+func (d *Driver) ListBuckets(ctx context.Context) ([]filestore.BucketInfo, error) {
+	if d.projectID == "" {
+		return nil, errs.New(errs.ErrKindInvalidInput, "gcs: ProjectID must be set to list buckets")
+	}
+
+	var buckets []filestore.BucketInfo
+	it := d.client.Buckets(ctx, d.projectID)
+	for {
+		b, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, mapError(err, "failed to list buckets")
+		}
+		buckets = append(buckets, filestore.BucketInfo{
+			Name:      b.Name,
+			CreatedAt: b.Created,
+		})
+	}
+	return buckets, nil
+}
+
+// ListObjects returns objects in bucket that match opts.
+func (d *Driver) ListObjects(ctx context.Context, bucket string, opts filestore.ListOptions) ([]filestore.ObjectInfo, error) {
+	var results []filestore.ObjectInfo
+	err := d.WalkObjects(ctx, bucket, opts, func(obj filestore.ObjectInfo) error {
+		results = append(results, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// WalkObjects calls fn for each object in bucket that matches opts, as the
+// listing pages in from GCS.
+func (d *Driver) WalkObjects(ctx context.Context, bucket string, opts filestore.ListOptions, fn func(filestore.ObjectInfo) error) error {
+	q := &storage.Query{Prefix: opts.Prefix, StartOffset: opts.Marker}
+	if !opts.Recursive {
+		q.Delimiter = "/"
+	}
+
+	count := 0
+	it := d.client.Bucket(bucket).Objects(ctx, q)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return mapError(err, "failed to list objects")
+		}
+
+		// StartOffset is inclusive; Marker is the last key already seen.
+		if opts.Marker != "" && attrs.Name == opts.Marker {
+			continue
+		}
+
+		var info filestore.ObjectInfo
+		if attrs.Prefix != "" {
+			info = filestore.ObjectInfo{Key: attrs.Prefix, IsDir: true}
+		} else {
+			info = toObjectInfo(attrs)
+		}
+
+		err = fn(info)
+		if errors.Is(err, filestore.ErrStopWalk) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		count++
+		if opts.Limit > 0 && count >= opts.Limit {
+			return nil
+		}
+	}
+}
+
+// GetObject opens a streaming handle to the object at key inside bucket.
+// The caller MUST call Object.Close() after reading.
+//
+// Objects stored with Content-Encoding: gzip are returned as stored rather
+// than transcoded by GCS, so Info().Size matches the bytes read; use
+// filestore.GetObjectDecompressed to decompress them.
+func (d *Driver) GetObject(ctx context.Context, bucket, key string) (filestore.Object, error) {
+	obj := d.client.Bucket(bucket).Object(key)
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		return nil, mapError(err, "failed to stat object")
+	}
+
+	// Pin the generation so content and metadata describe the same version.
+	r, err := obj.Generation(attrs.Generation).ReadCompressed(true).NewReader(ctx)
+	if err != nil {
+		return nil, mapError(err, "failed to get object")
+	}
+
+	info := toObjectInfo(attrs)
+	return &object{Reader: r, info: &info}, nil
+}
+
+// StatObject returns metadata for the object at key inside bucket
+// without downloading its content.
+func (d *Driver) StatObject(ctx context.Context, bucket, key string) (*filestore.ObjectInfo, error) {
+	attrs, err := d.client.Bucket(bucket).Object(key).Attrs(ctx)
+	if err != nil {
+		return nil, mapError(err, "failed to stat object")
+	}
+	info := toObjectInfo(attrs)
+	return &info, nil
+}
+
+// PresignGetURL returns a time-limited V4 signed URL for downloading the object.
+// With Application Default Credentials, signing uses the IAM signBlob API,
+// which requires the iam.serviceAccounts.signBlob permission.
+func (d *Driver) PresignGetURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	return d.signedURL(bucket, key, "GET", ttl)
+}
+
+// PresignPutURL returns a time-limited V4 signed URL that allows uploading
+// the object at key inside bucket with an HTTP PUT, without credentials.
+func (d *Driver) PresignPutURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	return d.signedURL(bucket, key, "PUT", ttl)
+}
+
+func (d *Driver) signedURL(bucket, key, method string, ttl time.Duration) (string, error) {
+	url, err := d.client.Bucket(bucket).SignedURL(key, &storage.SignedURLOptions{
+		Method:  method,
+		Expires: d.clock.Now().Add(ttl),
+		Scheme:  storage.SigningSchemeV4,
+	})
+	if err != nil {
+		return "", mapError(err, "failed to presign URL")
+	}
+	return url, nil
+}
+
+// --- helpers ---
+
+func toObjectInfo(attrs *storage.ObjectAttrs) filestore.ObjectInfo {
+	return filestore.ObjectInfo{
+		Key:             attrs.Name,
+		Size:            attrs.Size,
+		ContentType:     attrs.ContentType,
+		ContentEncoding: attrs.ContentEncoding,
+		ETag:            attrs.Etag,
+		LastModified:    attrs.Updated,
+		Encryption:      encryption(attrs),
+		KMSKeyID:        attrs.KMSKeyName,
+		Metadata:        attrs.Metadata,
+	}
+}
+
+// encryption reports how attrs' object is encrypted. GCS encrypts every
+// object at rest, with Google-managed keys unless a KMS or customer key is
+// used.
+func encryption(attrs *storage.ObjectAttrs) filestore.Encryption {
+	switch {
+	case attrs.CustomerKeySHA256 != "":
+		return filestore.EncryptionSSEC
+	case attrs.KMSKeyName != "":
+		return filestore.EncryptionSSEKMS
+	default:
+		return filestore.EncryptionSSES3
+	}
+}
+
+// object wraps *storage.Reader to satisfy filestore.Object.
+type object struct {
+	*storage.Reader
+	info *filestore.ObjectInfo
+}
+
+func (o *object) Info() *filestore.ObjectInfo {
+	return o.info
+}
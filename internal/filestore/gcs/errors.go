@@ -0,0 +1,46 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"cloud.google.com/go/storage"
+	"github.com/koustreak/DatRi/internal/errs"
+	"google.golang.org/api/googleapi"
+)
+
+// mapError translates a GCS client error into a *errs.Error.
+// It mirrors the mapError pattern used by the other drivers.
+func mapError(err error, msg string) *errs.Error {
+	if err == nil {
+		return nil
+	}
+
+	// Context cancellation / deadline
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return errs.Wrap(errs.ErrKindTimeout, msg, err)
+	}
+
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) {
+		return errs.Wrap(errs.ErrKindNotFound, msg, err)
+	}
+
+	// JSON API errors carry the HTTP status
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusNotFound:
+			return errs.Wrap(errs.ErrKindNotFound, msg, err)
+		case http.StatusForbidden, http.StatusUnauthorized:
+			return errs.Wrap(errs.ErrKindPermissionDenied, msg, err)
+		case http.StatusBadRequest:
+			return errs.Wrap(errs.ErrKindInvalidInput, msg, err)
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return errs.Wrap(errs.ErrKindTimeout, msg, err)
+		}
+	}
+
+	// Anything else — treat as a generic connection / I/O failure
+	return errs.Wrap(errs.ErrKindConnectionFailed, msg, err)
+}
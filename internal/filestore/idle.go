@@ -0,0 +1,106 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/clock"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// WithIdleTimeout returns a Store whose GetObject handles fail with
+// ErrKindTimeout when a single Read waits longer than timeout for data, so
+// a stalled connection cannot hang a download forever. The timeout bounds
+// the gap between bytes, not the whole download: a slow but steady
+// transfer is never interrupted.
+//
+// When the timeout fires the context the object was opened with is
+// cancelled to abort the pending Read; closing the object instead would
+// deadlock against drivers, like minio-go, whose Close waits for the Read
+// to finish. The handle must still be closed by the caller. All other
+// operations pass through to store unchanged.
+func WithIdleTimeout(store Store, timeout time.Duration) Store {
+	return &idleTimeoutStore{Store: store, timeout: timeout, clock: clock.Real}
+}
+
+type idleTimeoutStore struct {
+	Store
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+// Unwrap returns the wrapped Store; see As.
+func (s *idleTimeoutStore) Unwrap() Store { return s.Store }
+
+func (s *idleTimeoutStore) GetObject(ctx context.Context, bucket, key string) (Object, error) {
+	if s.timeout <= 0 {
+		return s.Store.GetObject(ctx, bucket, key)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	obj, err := s.Store.GetObject(ctx, bucket, key)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &idleTimeoutObject{Object: obj, timeout: s.timeout, clock: s.clock, cancel: cancel}, nil
+}
+
+// idleTimeoutObject cancels the wrapped Object's context when a Read stalls.
+type idleTimeoutObject struct {
+	Object
+	timeout time.Duration
+	clock   clock.Clock
+	cancel  context.CancelFunc
+
+	mu       sync.Mutex
+	timedOut bool
+}
+
+func (o *idleTimeoutObject) Read(p []byte) (int, error) {
+	if o.expired() {
+		return 0, o.timeoutError()
+	}
+
+	timer := o.clock.NewTimer(o.timeout)
+	done := make(chan struct{})
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		select {
+		case <-timer.C():
+			o.mu.Lock()
+			o.timedOut = true
+			o.mu.Unlock()
+			o.cancel()
+		case <-done:
+		}
+	}()
+
+	n, err := o.Object.Read(p)
+	timer.Stop()
+	close(done)
+	<-watched
+	if o.expired() {
+		// The error from the cancelled read is not meaningful.
+		return n, o.timeoutError()
+	}
+	return n, err
+}
+
+func (o *idleTimeoutObject) Close() error {
+	err := o.Object.Close()
+	o.cancel()
+	return err
+}
+func (o *idleTimeoutObject) expired() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.timedOut
+}
+
+func (o *idleTimeoutObject) timeoutError() error {
+	return errs.New(errs.ErrKindTimeout,
+		fmt.Sprintf("no data received from object %q for %s", o.Info().Key, o.timeout))
+}
@@ -0,0 +1,153 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/errs"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// storeMetrics are the collectors shared by every Store wrapped with the
+// same Registerer.
+type storeMetrics struct {
+	duration  *prometheus.HistogramVec // labels: op, err_kind
+	errors    *prometheus.CounterVec   // labels: op, err_kind
+	bytesRead prometheus.Counter
+}
+
+// WithMetrics returns a Store that records Prometheus metrics for every
+// operation on store:
+//
+//	datri_filestore_operation_duration_seconds{op, err_kind}  histogram
+//	datri_filestore_errors_total{op, err_kind}                counter
+//	datri_filestore_bytes_read_total                          counter
+//
+// err_kind is the errs.ErrKind of a failed call, or "none". For GetObject
+// the duration covers opening the object, and bytes are counted as the
+// caller reads them, so a partially read object counts only what was read.
+//
+// Wrapping several stores with the same reg shares the collectors. It
+// panics if reg rejects them for any other reason, like MustRegister.
+func WithMetrics(store Store, reg prometheus.Registerer) Store {
+	m := &storeMetrics{
+		duration: register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "datri_filestore_operation_duration_seconds",
+			Help:    "Latency of file store operations.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"op", "err_kind"})),
+		errors: register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "datri_filestore_errors_total",
+			Help: "File store operations that returned an error.",
+		}, []string{"op", "err_kind"})),
+		bytesRead: register(reg, prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "datri_filestore_bytes_read_total",
+			Help: "Bytes read from objects opened with GetObject.",
+		})),
+	}
+	return &metricsStore{next: store, m: m}
+}
+
+// register registers c with reg, returning the already-registered
+// collector instead when an identical one exists.
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) C {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
+
+type metricsStore struct {
+	next Store
+	m    *storeMetrics
+}
+
+// Unwrap returns the wrapped Store; see As.
+func (s *metricsStore) Unwrap() Store { return s.next }
+
+// observe records one operation that started at start.
+func (s *metricsStore) observe(op string, start time.Time, err error) {
+	kind := "none"
+	if err != nil {
+		kind = errs.KindOf(err).String()
+		s.m.errors.WithLabelValues(op, kind).Inc()
+	}
+	s.m.duration.WithLabelValues(op, kind).Observe(time.Since(start).Seconds())
+}
+
+func (s *metricsStore) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := s.next.Ping(ctx)
+	s.observe("ping", start, err)
+	return err
+}
+
+func (s *metricsStore) Close() error {
+	return s.next.Close()
+}
+
+func (s *metricsStore) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
+	start := time.Now()
+	buckets, err := s.next.ListBuckets(ctx)
+	s.observe("list_buckets", start, err)
+	return buckets, err
+}
+
+func (s *metricsStore) ListObjects(ctx context.Context, bucket string, opts ListOptions) ([]ObjectInfo, error) {
+	start := time.Now()
+	objects, err := s.next.ListObjects(ctx, bucket, opts)
+	s.observe("list_objects", start, err)
+	return objects, err
+}
+
+func (s *metricsStore) WalkObjects(ctx context.Context, bucket string, opts ListOptions, fn func(ObjectInfo) error) error {
+	start := time.Now()
+	err := s.next.WalkObjects(ctx, bucket, opts, fn)
+	s.observe("walk_objects", start, err)
+	return err
+}
+
+func (s *metricsStore) GetObject(ctx context.Context, bucket, key string) (Object, error) {
+	start := time.Now()
+	obj, err := s.next.GetObject(ctx, bucket, key)
+	s.observe("get_object", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &countingObject{Object: obj, counter: s.m.bytesRead}, nil
+}
+
+func (s *metricsStore) StatObject(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	start := time.Now()
+	info, err := s.next.StatObject(ctx, bucket, key)
+	s.observe("stat_object", start, err)
+	return info, err
+}
+
+func (s *metricsStore) PresignGetURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	start := time.Now()
+	url, err := s.next.PresignGetURL(ctx, bucket, key, ttl)
+	s.observe("presign_get_url", start, err)
+	return url, err
+}
+
+// countingObject adds every byte read to counter.
+type countingObject struct {
+	Object
+	counter prometheus.Counter
+}
+
+func (o *countingObject) Read(p []byte) (int, error) {
+	n, err := o.Object.Read(p)
+	if n > 0 {
+		o.counter.Add(float64(n))
+	}
+	return n, err
+}
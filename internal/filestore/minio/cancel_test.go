@@ -0,0 +1,229 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/errs"
+	"github.com/koustreak/DatRi/internal/filestore"
+)
+
+// fakeS3 is a minimal S3 endpoint. The first listing page is truncated and
+// the second never arrives; object bodies send one chunk and then stall.
+// Stalled handlers wait for the client to go away. HEAD requests are
+// answered in full so the stat before a GET does not stall.
+type fakeS3 struct {
+	srv      *httptest.Server
+	stalled  chan struct{} // receives once per stalled request
+	firstKey string
+}
+
+func newFakeS3(t *testing.T) *fakeS3 {
+	t.Helper()
+	f := &fakeS3{stalled: make(chan struct{}, 16), firstKey: "a.txt"}
+	f.srv = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+const lastModified = "2024-01-02T03:04:05.000Z"
+
+func (f *fakeS3) serve(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/":
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<ListAllMyBucketsResult><Owner><ID>owner</ID></Owner>`+
+			`<Buckets><Bucket><Name>bucket</Name><CreationDate>%s</CreationDate></Bucket></Buckets>`+
+			`</ListAllMyBucketsResult>`, lastModified)
+
+	case r.URL.Path == "/bucket/" || r.URL.Path == "/bucket":
+		if r.URL.Query().Get("continuation-token") != "" {
+			f.stall(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<ListBucketResult><Name>bucket</Name><Prefix></Prefix><KeyCount>1</KeyCount>`+
+			`<MaxKeys>1000</MaxKeys><IsTruncated>true</IsTruncated><NextContinuationToken>page2</NextContinuationToken>`+
+			`<Contents><Key>%s</Key><LastModified>%s</LastModified><ETag>"abc"</ETag><Size>3</Size>`+
+			`<StorageClass>STANDARD</StorageClass></Contents></ListBucketResult>`, f.firstKey, lastModified)
+
+	case strings.HasPrefix(r.URL.Path, "/bucket/"):
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", "1024")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Last-Modified", "Tue, 02 Jan 2024 03:04:05 GMT")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write([]byte(strings.Repeat("x", 16)))
+		w.(http.Flusher).Flush()
+		f.stall(w, r)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// stall blocks the request until the client cancels it.
+func (f *fakeS3) stall(_ http.ResponseWriter, r *http.Request) {
+	f.stalled <- struct{}{}
+	<-r.Context().Done()
+}
+
+func (f *fakeS3) driver(t *testing.T) *Driver {
+	t.Helper()
+	d, err := New(context.Background(), &filestore.Config{
+		Provider:  filestore.ProviderMinIO,
+		Endpoint:  strings.TrimPrefix(f.srv.URL, "http://"),
+		AccessKey: "access",
+		SecretKey: "secret",
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return d
+}
+
+// waitForStall fails the test unless the server reports a stalled request.
+func (f *fakeS3) waitForStall(t *testing.T) {
+	t.Helper()
+	select {
+	case <-f.stalled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request never reached the stalling handler")
+	}
+}
+
+// checkGoroutines fails the test if the goroutine count does not settle
+// back to base once the server and its connections are gone.
+func checkGoroutines(t *testing.T, base int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= base {
+			return
+		}
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			buf = buf[:runtime.Stack(buf, true)]
+			t.Fatalf("goroutines leaked: %d running, want at most %d\n%s", n, base, buf)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWalkObjectsCancelledMidStream(t *testing.T) {
+	base := runtime.NumGoroutine()
+	f := newFakeS3(t)
+	d := f.driver(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stalled := make(chan bool, 1)
+	go func() {
+		select {
+		case <-f.stalled:
+			stalled <- true
+		case <-time.After(5 * time.Second):
+			stalled <- false
+		}
+		cancel()
+	}()
+
+	var keys []string
+	err := d.WalkObjects(ctx, "bucket", filestore.ListOptions{Recursive: true}, func(obj filestore.ObjectInfo) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if !<-stalled {
+		t.Fatal("the second listing page was never requested")
+	}
+	if got := errs.KindOf(err); got != errs.ErrKindTimeout {
+		t.Fatalf("WalkObjects error kind = %v (%v), want %v", got, err, errs.ErrKindTimeout)
+	}
+	if len(keys) != 1 || keys[0] != f.firstKey {
+		t.Errorf("walked keys = %v, want [%s]", keys, f.firstKey)
+	}
+
+	f.srv.Close()
+	checkGoroutines(t, base)
+}
+
+func TestObjectReadCancelledMidStream(t *testing.T) {
+	base := runtime.NumGoroutine()
+	f := newFakeS3(t)
+	d := f.driver(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	obj, err := d.GetObject(ctx, "bucket", "a.txt")
+	if err != nil {
+		t.Fatalf("GetObject: %v", err)
+	}
+	// GetObject only stats the object; the body is requested by the first
+	// read.
+	buf := make([]byte, 16)
+	if _, err := io.ReadFull(obj, buf); err != nil {
+		t.Fatalf("reading the first chunk: %v", err)
+	}
+	f.waitForStall(t)
+
+	// The next read blocks on the stalled body until ctx is cancelled.
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = io.ReadAll(obj)
+	if got := errs.KindOf(err); got != errs.ErrKindTimeout {
+		t.Fatalf("Read error kind = %v (%v), want %v", got, err, errs.ErrKindTimeout)
+	}
+	if err := obj.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	f.srv.Close()
+	checkGoroutines(t, base)
+}
+
+func TestIdleTimeoutAbortsStalledRead(t *testing.T) {
+	base := runtime.NumGoroutine()
+	f := newFakeS3(t)
+	store := filestore.WithIdleTimeout(f.driver(t), 200*time.Millisecond)
+
+	obj, err := store.GetObject(context.Background(), "bucket", "a.txt")
+	if err != nil {
+		t.Fatalf("GetObject: %v", err)
+	}
+	buf := make([]byte, 16)
+	if _, err := io.ReadFull(obj, buf); err != nil {
+		t.Fatalf("reading the first chunk: %v", err)
+	}
+	f.waitForStall(t)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(obj)
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		if got := errs.KindOf(err); got != errs.ErrKindTimeout {
+			t.Fatalf("Read error kind = %v (%v), want %v", got, err, errs.ErrKindTimeout)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stalled Read did not return after the idle timeout")
+	}
+	if err := obj.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	f.srv.Close()
+	checkGoroutines(t, base)
+}
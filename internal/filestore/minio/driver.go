@@ -12,7 +12,11 @@ package minio
 
 import (
 	"context"
+	"errors"
 	"io"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/koustreak/DatRi/internal/errs"
@@ -23,23 +27,53 @@ import (
 
 // Driver is a MinIO implementation of filestore.Store.
 // It is safe for concurrent use by multiple goroutines.
+//
+// Bucket operations that fail because the bucket lives in a region other
+// than cfg.Region are retried once against that region, which is then
+// remembered for the bucket.
 type Driver struct {
-	client *miniogo.Client
+	client   *miniogo.Client
+	endpoint string
+	opts     miniogo.Options // used to build regional clients
+
+	mu            sync.RWMutex
+	bucketClients map[string]*miniogo.Client // bucket → client for its region
+	regionClients map[string]*miniogo.Client // region → client
+}
+
+func init() {
+	filestore.Register(filestore.ProviderMinIO, func(ctx context.Context, cfg *filestore.Config) (filestore.Store, error) {
+		d, err := New(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	})
 }
 
 // New connects to MinIO using the provided Config and returns a Driver.
 // It calls Ping to validate the connection before returning.
 func New(ctx context.Context, cfg *filestore.Config) (*Driver, error) {
-	client, err := miniogo.New(cfg.Endpoint, &miniogo.Options{
-		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	opts := miniogo.Options{
+		Creds:  newCredentials(cfg),
 		Secure: cfg.UseSSL,
 		Region: cfg.Region,
-	})
+	}
+	client, err := miniogo.New(cfg.Endpoint, &opts)
 	if err != nil {
 		return nil, errs.Wrap(errs.ErrKindConnectionFailed, "failed to create minio client", err)
 	}
 
-	d := &Driver{client: client}
+	d := &Driver{
+		client:        client,
+		endpoint:      cfg.Endpoint,
+		opts:          opts,
+		bucketClients: make(map[string]*miniogo.Client),
+		regionClients: make(map[string]*miniogo.Client),
+	}
 
 	if err := d.Ping(ctx); err != nil {
 		return nil, err
@@ -48,6 +82,24 @@ func New(ctx context.Context, cfg *filestore.Config) (*Driver, error) {
 	return d, nil
 }
 
+// newCredentials returns the provider for cfg.Credentials. The ambient
+// sources are resolved lazily, on the first request that needs them.
+func newCredentials(cfg *filestore.Config) *credentials.Credentials {
+	switch cfg.Credentials {
+	case filestore.CredentialsEnv:
+		return credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.EnvMinio{},
+		})
+	case filestore.CredentialsIAMRole:
+		return credentials.NewIAM("")
+	case filestore.CredentialsProfile:
+		return credentials.NewFileAWSCredentials(cfg.CredentialsFile, cfg.Profile)
+	default:
+		return credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	}
+}
+
 // --- filestore.Store implementation ---
 
 // Ping verifies the MinIO server is reachable by listing buckets.
@@ -83,59 +135,140 @@ func (d *Driver) ListBuckets(ctx context.Context) ([]filestore.BucketInfo, error
 
 // ListObjects returns objects in bucket that match opts.
 func (d *Driver) ListObjects(ctx context.Context, bucket string, opts filestore.ListOptions) ([]filestore.ObjectInfo, error) {
+	var results []filestore.ObjectInfo
+	err := d.WalkObjects(ctx, bucket, opts, func(obj filestore.ObjectInfo) error {
+		results = append(results, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// WalkObjects calls fn for each object in bucket that matches opts, as the
+// listing streams in from MinIO.
+func (d *Driver) WalkObjects(ctx context.Context, bucket string, opts filestore.ListOptions, fn func(filestore.ObjectInfo) error) error {
 	listOpts := miniogo.ListObjectsOptions{
-		Prefix:    opts.Prefix,
-		Recursive: opts.Recursive,
+		Prefix:       opts.Prefix,
+		Recursive:    opts.Recursive,
+		WithMetadata: opts.WithMetadata,
 	}
 
-	var results []filestore.ObjectInfo
-	count := 0
+	// Cancelling on return stops the SDK's listing goroutine when we exit
+	// before draining the channel.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	for obj := range d.client.ListObjects(ctx, bucket, listOpts) {
+	objects := d.clientFor(bucket).ListObjects(ctx, bucket, listOpts)
+	count := 0
+	redirected := false
+	for {
+		var obj miniogo.ObjectInfo
+		var ok bool
+		// Select on ctx so a cancelled walk returns promptly even while the
+		// SDK is blocked on a slow listing page.
+		select {
+		case <-ctx.Done():
+			return mapError(ctx.Err(), "failed to list objects")
+		case obj, ok = <-objects:
+		}
+		if !ok {
+			break
+		}
 		if obj.Err != nil {
-			return nil, mapError(obj.Err, "failed to list objects")
+			// A wrong-region error arrives before any object; restart the
+			// listing against the bucket's region.
+			if count == 0 && !redirected {
+				if c, ok := d.redirect(ctx, bucket, obj.Err); ok {
+					redirected = true
+					objects = c.ListObjects(ctx, bucket, listOpts)
+					continue
+				}
+			}
+			return mapError(obj.Err, "failed to list objects")
 		}
 
-		results = append(results, filestore.ObjectInfo{
+		info := filestore.ObjectInfo{
 			Key:          obj.Key,
 			Size:         obj.Size,
 			ContentType:  obj.ContentType,
 			ETag:         obj.ETag,
 			LastModified: obj.LastModified,
-			IsDir:        obj.Key[len(obj.Key)-1] == '/',
-		})
+			IsDir:        isDir(obj, opts.Recursive),
+		}
+		if opts.WithMetadata && !info.IsDir {
+			if obj.UserMetadata != nil {
+				info.Metadata = listedMetadata(obj.UserMetadata)
+			} else {
+				// The server ignored metadata=true; fetch it per object.
+				stat, err := d.StatObject(ctx, bucket, obj.Key)
+				if err != nil {
+					return err
+				}
+				info.Metadata = stat.Metadata
+			}
+		}
+
+		err := fn(info)
+		if errors.Is(err, filestore.ErrStopWalk) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 
 		count++
 		if opts.Limit > 0 && count >= opts.Limit {
-			break
+			return nil
 		}
 	}
 
-	return results, nil
+	// The SDK may close the channel without an error when ctx is cancelled.
+	if err := ctx.Err(); err != nil {
+		return mapError(err, "failed to list objects")
+	}
+	return nil
 }
 
 // GetObject opens a streaming handle to the object at key inside bucket.
 // The caller MUST call Object.Close() after reading.
 func (d *Driver) GetObject(ctx context.Context, bucket, key string) (filestore.Object, error) {
-	obj, err := d.client.GetObject(ctx, bucket, key, miniogo.GetObjectOptions{})
-	if err != nil {
-		return nil, mapError(err, "failed to get object")
+	// GetObject is lazy: a wrong-region error only surfaces from Stat.
+	type opened struct {
+		obj  *miniogo.Object
+		stat miniogo.ObjectInfo
 	}
-
-	stat, err := obj.Stat()
+	res, err := withRegion(ctx, d, bucket, func(c *miniogo.Client) (opened, error) {
+		obj, err := c.GetObject(ctx, bucket, key, miniogo.GetObjectOptions{})
+		if err != nil {
+			return opened{}, err
+		}
+		stat, err := obj.Stat()
+		if err != nil {
+			obj.Close()
+			return opened{}, err
+		}
+		return opened{obj, stat}, nil
+	})
 	if err != nil {
-		obj.Close()
-		return nil, mapError(err, "failed to stat object after get")
+		return nil, mapError(err, "failed to get object")
 	}
+	obj, stat := res.obj, res.stat
 
 	return &object{
 		ReadCloser: obj,
+		ctx:        ctx,
 		info: &filestore.ObjectInfo{
-			Key:          key,
-			Size:         stat.Size,
-			ContentType:  stat.ContentType,
-			ETag:         stat.ETag,
-			LastModified: stat.LastModified,
+			Key:             key,
+			Size:            stat.Size,
+			ContentType:     stat.ContentType,
+			ETag:            stat.ETag,
+			LastModified:    stat.LastModified,
+			ContentEncoding: stat.Metadata.Get("Content-Encoding"),
+			Encryption:      encryption(stat.Metadata),
+			KMSKeyID:        stat.Metadata.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"),
+			Metadata:        stat.UserMetadata,
 		},
 	}, nil
 }
@@ -143,37 +276,101 @@ func (d *Driver) GetObject(ctx context.Context, bucket, key string) (filestore.O
 // StatObject returns metadata for the object at key inside bucket
 // without downloading its content.
 func (d *Driver) StatObject(ctx context.Context, bucket, key string) (*filestore.ObjectInfo, error) {
-	stat, err := d.client.StatObject(ctx, bucket, key, miniogo.StatObjectOptions{})
+	stat, err := withRegion(ctx, d, bucket, func(c *miniogo.Client) (miniogo.ObjectInfo, error) {
+		return c.StatObject(ctx, bucket, key, miniogo.StatObjectOptions{})
+	})
 	if err != nil {
 		return nil, mapError(err, "failed to stat object")
 	}
 
 	return &filestore.ObjectInfo{
-		Key:          stat.Key,
-		Size:         stat.Size,
-		ContentType:  stat.ContentType,
-		ETag:         stat.ETag,
-		LastModified: stat.LastModified,
+		Key:             stat.Key,
+		Size:            stat.Size,
+		ContentType:     stat.ContentType,
+		ETag:            stat.ETag,
+		LastModified:    stat.LastModified,
+		ContentEncoding: stat.Metadata.Get("Content-Encoding"),
+		Encryption:      encryption(stat.Metadata),
+		KMSKeyID:        stat.Metadata.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"),
+		Metadata:        stat.UserMetadata,
 	}, nil
 }
 
 // PresignGetURL returns a time-limited public download URL for the object.
 func (d *Driver) PresignGetURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
-	u, err := d.client.PresignedGetObject(ctx, bucket, key, ttl, nil)
+	u, err := d.clientFor(bucket).PresignedGetObject(ctx, bucket, key, ttl, nil)
 	if err != nil {
 		return "", mapError(err, "failed to generate presigned URL")
 	}
 	return u.String(), nil
 }
 
+// isDir reports whether a listing entry is a virtual directory. In a
+// non-recursive listing the SDK delivers common prefixes as entries with
+// only Key set; real objects always carry an ETag and modification time.
+// Zero-byte "folder marker" objects whose key ends in '/' also count.
+func isDir(obj miniogo.ObjectInfo, recursive bool) bool {
+	if !recursive && obj.ETag == "" && obj.LastModified.IsZero() {
+		return true
+	}
+	return strings.HasSuffix(obj.Key, "/") && obj.Size == 0
+}
+
+// listedMetadata returns the user metadata of a listing entry. MinIO
+// lists it with its X-Amz-Meta- header prefix, next to system keys such as
+// content-type, which are dropped.
+func listedMetadata(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if len(k) > len(userMetaPrefix) && strings.EqualFold(k[:len(userMetaPrefix)], userMetaPrefix) {
+			out[http.CanonicalHeaderKey(k[len(userMetaPrefix):])] = v
+		}
+	}
+	return out
+}
+
+const userMetaPrefix = "X-Amz-Meta-"
+
 // --- internal types ---
 
 // object wraps a MinIO GetObject response and exposes filestore.Object.
 type object struct {
 	io.ReadCloser
+	ctx  context.Context // the GetObject context; reads fail once it is done
 	info *filestore.ObjectInfo
 }
 
+// Read fails with ErrKindTimeout as soon as the GetObject context is done,
+// even if the SDK still has buffered data, and maps other read errors.
+func (o *object) Read(p []byte) (int, error) {
+	if err := o.ctx.Err(); err != nil {
+		return 0, mapError(err, "failed to read object")
+	}
+	n, err := o.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		if ctxErr := o.ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return n, mapError(err, "failed to read object")
+	}
+	return n, err
+}
+
 func (o *object) Info() *filestore.ObjectInfo {
 	return o.info
 }
+
+// encryption reads an object's server-side encryption from its response
+// headers.
+func encryption(h http.Header) filestore.Encryption {
+	switch {
+	case h.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "":
+		return filestore.EncryptionSSEC
+	case strings.HasPrefix(h.Get("X-Amz-Server-Side-Encryption"), "aws:kms"):
+		return filestore.EncryptionSSEKMS
+	case h.Get("X-Amz-Server-Side-Encryption") != "":
+		return filestore.EncryptionSSES3
+	default:
+		return filestore.EncryptionNone
+	}
+}
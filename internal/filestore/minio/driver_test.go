@@ -0,0 +1,90 @@
+package minio
+
+import (
+	"testing"
+	"time"
+
+	miniogo "github.com/minio/minio-go/v7"
+)
+
+func TestIsDir(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		obj       miniogo.ObjectInfo
+		recursive bool
+		want      bool
+	}{
+		{
+			name: "common prefix in flat listing",
+			obj:  miniogo.ObjectInfo{Key: "logs/"},
+			want: true,
+		},
+		{
+			name:      "key without metadata in recursive listing",
+			obj:       miniogo.ObjectInfo{Key: "logs"},
+			recursive: true,
+			want:      false,
+		},
+		{
+			name: "object in flat listing",
+			obj:  miniogo.ObjectInfo{Key: "logs/a.txt", ETag: "abc", Size: 3, LastModified: modified},
+			want: false,
+		},
+		{
+			name:      "object in recursive listing",
+			obj:       miniogo.ObjectInfo{Key: "logs/a.txt", ETag: "abc", Size: 3, LastModified: modified},
+			recursive: true,
+			want:      false,
+		},
+		{
+			name:      "zero-byte folder marker",
+			obj:       miniogo.ObjectInfo{Key: "logs/", ETag: "d41d8", LastModified: modified},
+			recursive: true,
+			want:      true,
+		},
+		{
+			name: "zero-byte folder marker in flat listing",
+			obj:  miniogo.ObjectInfo{Key: "logs/", ETag: "d41d8", LastModified: modified},
+			want: true,
+		},
+		{
+			name:      "non-empty object whose key ends in slash",
+			obj:       miniogo.ObjectInfo{Key: "logs/", ETag: "abc", Size: 3, LastModified: modified},
+			recursive: true,
+			want:      false,
+		},
+		{
+			name:      "zero-byte object without trailing slash",
+			obj:       miniogo.ObjectInfo{Key: "logs/empty", ETag: "d41d8", LastModified: modified},
+			recursive: true,
+			want:      false,
+		},
+		{
+			name:      "empty key object",
+			obj:       miniogo.ObjectInfo{Key: "", ETag: "abc", Size: 3, LastModified: modified},
+			recursive: true,
+			want:      false,
+		},
+		{
+			name:      "empty key without metadata in recursive listing",
+			obj:       miniogo.ObjectInfo{Key: ""},
+			recursive: true,
+			want:      false,
+		},
+		{
+			name: "empty key without metadata in flat listing",
+			obj:  miniogo.ObjectInfo{Key: ""},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDir(tt.obj, tt.recursive); got != tt.want {
+				t.Errorf("isDir(%+v, %v) = %v, want %v", tt.obj, tt.recursive, got, tt.want)
+			}
+		})
+	}
+}
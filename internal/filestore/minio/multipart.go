@@ -0,0 +1,96 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/koustreak/DatRi/internal/errs"
+	"github.com/koustreak/DatRi/internal/filestore"
+	miniogo "github.com/minio/minio-go/v7"
+)
+
+// maxPartNumber is the S3 limit on parts per upload.
+const maxPartNumber = 10000
+
+var _ filestore.MultipartUploader = (*Driver)(nil)
+
+// NewMultipartUpload starts a multipart upload using MinIO's low-level
+// Core API.
+func (d *Driver) NewMultipartUpload(ctx context.Context, bucket, key string) (filestore.Upload, error) {
+	core := miniogo.Core{Client: d.clientFor(bucket)}
+	id, err := core.NewMultipartUpload(ctx, bucket, key, miniogo.PutObjectOptions{})
+	if c, ok := d.redirect(ctx, bucket, err); ok {
+		core = miniogo.Core{Client: c}
+		id, err = core.NewMultipartUpload(ctx, bucket, key, miniogo.PutObjectOptions{})
+	}
+	if err != nil {
+		return nil, mapError(err, "failed to start multipart upload")
+	}
+	return &upload{core: core, bucket: bucket, key: key, id: id, parts: map[int]string{}}, nil
+}
+
+// upload implements filestore.Upload. It is safe for concurrent use.
+type upload struct {
+	core   miniogo.Core
+	bucket string
+	key    string
+	id     string
+
+	mu    sync.Mutex
+	parts map[int]string // part number → ETag
+}
+
+func (u *upload) ID() string { return u.id }
+
+func (u *upload) UploadPart(ctx context.Context, partNum int, r io.Reader, size int64) error {
+	if partNum < 1 || partNum > maxPartNumber {
+		return errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("part number must be between 1 and %d, got %d", maxPartNumber, partNum))
+	}
+
+	part, err := u.core.PutObjectPart(ctx, u.bucket, u.key, u.id, partNum, r, size, miniogo.PutObjectPartOptions{})
+	if err != nil {
+		return mapError(err, fmt.Sprintf("failed to upload part %d", partNum))
+	}
+
+	u.mu.Lock()
+	u.parts[partNum] = part.ETag
+	u.mu.Unlock()
+	return nil
+}
+
+func (u *upload) Complete(ctx context.Context) (*filestore.ObjectInfo, error) {
+	u.mu.Lock()
+	parts := make([]miniogo.CompletePart, 0, len(u.parts))
+	for n, etag := range u.parts {
+		parts = append(parts, miniogo.CompletePart{PartNumber: n, ETag: etag})
+	}
+	u.mu.Unlock()
+
+	if len(parts) == 0 {
+		return nil, errs.New(errs.ErrKindInvalidInput, "cannot complete a multipart upload with no parts")
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	info, err := u.core.CompleteMultipartUpload(ctx, u.bucket, u.key, u.id, parts, miniogo.PutObjectOptions{})
+	if err != nil {
+		return nil, mapError(err, "failed to complete multipart upload")
+	}
+
+	return &filestore.ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+func (u *upload) Abort(ctx context.Context) error {
+	if err := u.core.AbortMultipartUpload(ctx, u.bucket, u.key, u.id); err != nil {
+		return mapError(err, "failed to abort multipart upload")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package minio
+
+import (
+	"context"
+	"errors"
+
+	miniogo "github.com/minio/minio-go/v7"
+)
+
+// regionErrorCodes are the S3 error codes returned when a request is signed
+// for, or sent to, a region other than the bucket's.
+var regionErrorCodes = map[string]bool{
+	"AuthorizationHeaderMalformed":       true,
+	"PermanentRedirect":                  true,
+	"IllegalLocationConstraintException": true,
+}
+
+// clientFor returns the client to use for bucket: a regional client once
+// the bucket's region has been learned, the default client otherwise.
+func (d *Driver) clientFor(bucket string) *miniogo.Client {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if c, ok := d.bucketClients[bucket]; ok {
+		return c
+	}
+	return d.client
+}
+
+// redirect handles a wrong-region error for bucket. It learns the bucket's
+// region — from the error response, else GetBucketLocation — caches a
+// client for it, and returns that client. ok is false when err is not a
+// region error or the region cannot be determined.
+func (d *Driver) redirect(ctx context.Context, bucket string, err error) (c *miniogo.Client, ok bool) {
+	var resp miniogo.ErrorResponse
+	if !errors.As(err, &resp) || !regionErrorCodes[resp.Code] {
+		return nil, false
+	}
+
+	region := resp.Region
+	if region == "" {
+		loc, locErr := d.client.GetBucketLocation(ctx, bucket)
+		if locErr != nil || loc == "" {
+			return nil, false
+		}
+		region = loc
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c, ok = d.regionClients[region]
+	if !ok {
+		opts := d.opts
+		opts.Region = region
+		var newErr error
+		if c, newErr = miniogo.New(d.endpoint, &opts); newErr != nil {
+			return nil, false
+		}
+		d.regionClients[region] = c
+	}
+	d.bucketClients[bucket] = c
+	return c, true
+}
+
+// withRegion runs op against bucket's client and, if it fails because the
+// bucket lives in another region, once more against a client for that
+// region. Errors are returned unmapped.
+func withRegion[T any](ctx context.Context, d *Driver, bucket string, op func(*miniogo.Client) (T, error)) (T, error) {
+	v, err := op(d.clientFor(bucket))
+	if err == nil {
+		return v, nil
+	}
+	c, ok := d.redirect(ctx, bucket, err)
+	if !ok {
+		return v, err
+	}
+	return op(c)
+}
@@ -0,0 +1,42 @@
+package filestore
+
+import (
+	"context"
+	"io"
+)
+
+// MultipartUploader is implemented by stores that support multipart
+// uploads, for objects too large to send reliably in one request. Look it
+// up with As, which also sees through decorators (each has an Unwrap
+// method returning the Store it wraps):
+//
+//	mu, ok := filestore.As[filestore.MultipartUploader](store)
+//
+// It is separate from Store because Store is read-only.
+type MultipartUploader interface {
+	// NewMultipartUpload starts an upload that becomes the object at key
+	// inside bucket once completed.
+	NewMultipartUpload(ctx context.Context, bucket, key string) (Upload, error)
+}
+
+// Upload is an in-progress multipart upload. Parts may be uploaded in any
+// order and concurrently. Every upload must end with Complete or Abort;
+// an abandoned upload keeps its parts (and their storage cost) on the
+// backend until it is aborted or expired by a lifecycle rule.
+type Upload interface {
+	// ID returns the backend's upload ID.
+	ID() string
+
+	// UploadPart uploads part number partNum (1 to 10000) with exactly
+	// size bytes read from r. Every part except the last must be at least
+	// 5 MiB. Uploading the same partNum again replaces it, so a failed
+	// part can simply be retried.
+	UploadPart(ctx context.Context, partNum int, r io.Reader, size int64) error
+
+	// Complete assembles the uploaded parts, in part-number order, into
+	// the final object.
+	Complete(ctx context.Context) (*ObjectInfo, error)
+
+	// Abort discards the upload and all uploaded parts.
+	Abort(ctx context.Context) error
+}
@@ -26,6 +26,10 @@ type ObjectInfo struct {
 	// ContentType is the MIME type (e.g. "image/jpeg").
 	ContentType string
 
+	// ContentEncoding is the Content-Encoding the object was stored with
+	// (e.g. "gzip"), or "" if none. Not populated by ListObjects.
+	ContentEncoding string
+
 	// ETag is the object's entity tag / hash, as returned by the backend.
 	ETag string
 
@@ -35,8 +39,42 @@ type ObjectInfo struct {
 	// IsDir is true when the entry represents a virtual directory (prefix),
 	// not an actual stored object.
 	IsDir bool
+
+	// Encryption is the server-side encryption the object is stored with.
+	// Not populated by ListObjects on MinIO.
+	Encryption Encryption
+
+	// KMSKeyID identifies the KMS key of an EncryptionSSEKMS object, when
+	// the backend reports it.
+	KMSKeyID string
+
+	// Metadata is the user-defined metadata stored with the object, keyed
+	// without the backend's prefix (x-amz-meta-). ListObjects fills it
+	// only when ListOptions.WithMetadata is set.
+	Metadata map[string]string
 }
 
+// Encryption is a kind of server-side encryption at rest.
+type Encryption string
+
+const (
+	// EncryptionNone: the object is stored unencrypted, or the backend
+	// does not report its encryption.
+	EncryptionNone Encryption = ""
+
+	// EncryptionSSES3: encrypted with keys the storage service manages
+	// (S3 SSE-S3, GCS default encryption).
+	EncryptionSSES3 Encryption = "SSE-S3"
+
+	// EncryptionSSEKMS: encrypted with a key held in a key management
+	// service; see ObjectInfo.KMSKeyID.
+	EncryptionSSEKMS Encryption = "SSE-KMS"
+
+	// EncryptionSSEC: encrypted with a key the client supplies on every
+	// request.
+	EncryptionSSEC Encryption = "SSE-C"
+)
+
 // Object is a streaming handle to an object's content.
 // The caller MUST call Close() after reading to avoid resource leaks.
 type Object interface {
@@ -63,4 +101,11 @@ type ListOptions struct {
 	// Marker is the pagination cursor — the last key seen in a previous page.
 	// Pass "" to start from the beginning.
 	Marker string
+
+	// WithMetadata fills ObjectInfo.Metadata for each listed object. GCS
+	// and MinIO servers return it in the listing itself. Other
+	// S3-compatible servers (including AWS S3) ignore the request, so the
+	// MinIO driver falls back to one StatObject per object — an extra round
+	// trip each, which makes large listings much slower.
+	WithMetadata bool
 }
@@ -0,0 +1,68 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// OpenFunc connects to a storage backend using cfg. Each provider package
+// registers one with Register.
+type OpenFunc func(ctx context.Context, cfg *Config) (Store, error)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[Provider]OpenFunc{}
+)
+
+// Register makes a provider available to Open under name. Provider
+// packages call it from init, so a program enables a provider by importing
+// its package, usually for side effects only:
+//
+//	import _ "github.com/koustreak/DatRi/internal/filestore/minio"
+//
+// Register panics if open is nil or name is already registered.
+func Register(name Provider, open OpenFunc) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if open == nil {
+		panic("filestore: Register open func is nil")
+	}
+	if _, dup := providers[name]; dup {
+		panic("filestore: Register called twice for provider " + string(name))
+	}
+	providers[name] = open
+}
+
+// Open connects to the storage backend described by cfg using the provider
+// registered for cfg.Provider, and returns it behind the Store interface.
+// It returns ErrKindInvalidInput if no such provider has been registered.
+func Open(ctx context.Context, cfg *Config) (Store, error) {
+	providersMu.RLock()
+	open, ok := providers[cfg.Provider]
+	providersMu.RUnlock()
+
+	if !ok {
+		return nil, errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("unknown filestore provider %q (registered: %s) — is its package imported?",
+				cfg.Provider, strings.Join(registeredProviders(), ", ")))
+	}
+	return open(ctx, cfg)
+}
+
+func registeredProviders() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,144 @@
+package filestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/clock"
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// RetryPolicy controls how WithRetry retries failed operations.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first.
+	// Values below 1 are treated as 1 (no retries).
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. Each later wait
+	// doubles, up to MaxBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Clock times the waits. Nil means clock.Real.
+	Clock clock.Clock
+}
+
+// DefaultRetryPolicy returns a policy suited to S3-compatible backends:
+// three attempts, backing off from 100ms to at most 2s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// IsRetryable reports whether err is worth retrying: timeouts (including
+// throttling such as S3 SlowDown and RequestTimeout) and connection
+// failures. Not-found, permission and validation errors are permanent.
+func IsRetryable(err error) bool {
+	return errs.IsTimeout(err) || errs.IsConnectionFailed(err)
+}
+
+// WithRetry returns a Store that retries operations failing with an
+// IsRetryable error, backing off exponentially per policy. It stops as soon
+// as ctx is done, returning the last error.
+//
+// Every Store operation is a read, so all are safe to retry. GetObject
+// retries opening the object only; a Read that fails mid-download is
+// returned to the caller. WalkObjects retries only while no object has
+// been passed to fn, so fn never sees an entry twice.
+func WithRetry(store Store, policy RetryPolicy) Store {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &retryStore{next: store, policy: policy}
+}
+
+type retryStore struct {
+	next   Store
+	policy RetryPolicy
+}
+
+// Unwrap returns the wrapped Store; see As.
+func (s *retryStore) Unwrap() Store { return s.next }
+
+// retry calls op until it succeeds, fails with an error canRetry rejects,
+// runs out of attempts, or ctx is done.
+func retry[T any](ctx context.Context, p RetryPolicy, canRetry func(error) bool, op func() (T, error)) (T, error) {
+	backoff := p.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		v, err := op()
+		if err == nil || attempt >= p.MaxAttempts || !canRetry(err) || ctx.Err() != nil {
+			return v, err
+		}
+
+		t := clock.Or(p.Clock).NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return v, err
+		case <-t.C():
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
+
+func (s *retryStore) Ping(ctx context.Context) error {
+	_, err := retry(ctx, s.policy, IsRetryable, func() (struct{}, error) {
+		return struct{}{}, s.next.Ping(ctx)
+	})
+	return err
+}
+
+func (s *retryStore) Close() error {
+	return s.next.Close()
+}
+
+func (s *retryStore) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
+	return retry(ctx, s.policy, IsRetryable, func() ([]BucketInfo, error) {
+		return s.next.ListBuckets(ctx)
+	})
+}
+
+func (s *retryStore) ListObjects(ctx context.Context, bucket string, opts ListOptions) ([]ObjectInfo, error) {
+	return retry(ctx, s.policy, IsRetryable, func() ([]ObjectInfo, error) {
+		return s.next.ListObjects(ctx, bucket, opts)
+	})
+}
+
+func (s *retryStore) WalkObjects(ctx context.Context, bucket string, opts ListOptions, fn func(ObjectInfo) error) error {
+	started := false
+	canRetry := func(err error) bool { return !started && IsRetryable(err) }
+	_, err := retry(ctx, s.policy, canRetry, func() (struct{}, error) {
+		return struct{}{}, s.next.WalkObjects(ctx, bucket, opts, func(obj ObjectInfo) error {
+			started = true
+			return fn(obj)
+		})
+	})
+	return err
+}
+
+func (s *retryStore) GetObject(ctx context.Context, bucket, key string) (Object, error) {
+	return retry(ctx, s.policy, IsRetryable, func() (Object, error) {
+		return s.next.GetObject(ctx, bucket, key)
+	})
+}
+
+func (s *retryStore) StatObject(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	return retry(ctx, s.policy, IsRetryable, func() (*ObjectInfo, error) {
+		return s.next.StatObject(ctx, bucket, key)
+	})
+}
+
+func (s *retryStore) PresignGetURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	return retry(ctx, s.policy, IsRetryable, func() (string, error) {
+		return s.next.PresignGetURL(ctx, bucket, key, ttl)
+	})
+}
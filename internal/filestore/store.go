@@ -5,8 +5,10 @@
 //
 // Usage:
 //
+//	import _ "github.com/koustreak/DatRi/internal/filestore/minio" // registers "minio"
+//
 //	cfg := filestore.DefaultConfig("localhost:9000", "minioadmin", "minioadmin")
-//	store, err := minio.New(ctx, cfg)
+//	store, err := filestore.Open(ctx, cfg)
 //	if err != nil { ... }
 //	defer store.Close()
 //
@@ -15,9 +17,14 @@ package filestore
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrStopWalk can be returned by a WalkObjects callback to end the walk
+// early without WalkObjects reporting an error.
+var ErrStopWalk = errors.New("stop walk")
+
 // Store is the single interface all file storage providers must implement.
 // Currently scoped to GET (read) operations only.
 type Store interface {
@@ -34,6 +41,12 @@ type Store interface {
 	// Virtual directory entries (common prefixes) are included when opts.Recursive is false.
 	ListObjects(ctx context.Context, bucket string, opts ListOptions) ([]ObjectInfo, error)
 
+	// WalkObjects calls fn for each object ListObjects would return, as the
+	// listing is streamed from the backend, without buffering it in memory.
+	// If fn returns an error the walk stops and WalkObjects returns it,
+	// except for ErrStopWalk, which stops the walk and returns nil.
+	WalkObjects(ctx context.Context, bucket string, opts ListOptions, fn func(ObjectInfo) error) error
+
 	// GetObject opens a streaming handle to the object at key inside bucket.
 	// The caller MUST call Object.Close() after reading.
 	GetObject(ctx context.Context, bucket, key string) (Object, error)
@@ -46,3 +59,27 @@ type Store interface {
 	// the object at key inside bucket without credentials.
 	PresignGetURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error)
 }
+
+// As finds the first store in the chain starting at store that implements
+// T, following Unwrap through decorators such as WithRetry and WithMetrics.
+// Decorators only forward Store itself, so optional interfaces like
+// MultipartUploader must be looked up this way:
+//
+//	mu, ok := filestore.As[filestore.MultipartUploader](store)
+//
+// The store found bypasses the decorators above it: its calls are not
+// retried, measured or traced.
+func As[T any](store Store) (T, bool) {
+	for store != nil {
+		if t, ok := store.(T); ok {
+			return t, true
+		}
+		u, ok := store.(interface{ Unwrap() Store })
+		if !ok {
+			break
+		}
+		store = u.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
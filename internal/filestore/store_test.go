@@ -0,0 +1,64 @@
+package filestore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/filestore"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// stubStore is a Store whose operations all succeed with empty results.
+type stubStore struct{}
+
+func (stubStore) Ping(context.Context) error                                  { return nil }
+func (stubStore) Close() error                                                { return nil }
+func (stubStore) ListBuckets(context.Context) ([]filestore.BucketInfo, error) { return nil, nil }
+func (stubStore) ListObjects(context.Context, string, filestore.ListOptions) ([]filestore.ObjectInfo, error) {
+	return nil, nil
+}
+func (stubStore) WalkObjects(context.Context, string, filestore.ListOptions, func(filestore.ObjectInfo) error) error {
+	return nil
+}
+func (stubStore) GetObject(context.Context, string, string) (filestore.Object, error) {
+	return nil, nil
+}
+func (stubStore) StatObject(context.Context, string, string) (*filestore.ObjectInfo, error) {
+	return nil, nil
+}
+func (stubStore) PresignGetURL(context.Context, string, string, time.Duration) (string, error) {
+	return "", nil
+}
+
+// multipartStore adds MultipartUploader to stubStore.
+type multipartStore struct{ stubStore }
+
+func (multipartStore) NewMultipartUpload(context.Context, string, string) (filestore.Upload, error) {
+	return nil, nil
+}
+
+func decorate(store filestore.Store) filestore.Store {
+	store = filestore.WithRetry(store, filestore.DefaultRetryPolicy())
+	store = filestore.WithIdleTimeout(store, time.Second)
+	store = filestore.WithMetrics(store, prometheus.NewRegistry())
+	return filestore.WithTracing(store, noop.NewTracerProvider().Tracer("test"))
+}
+
+func TestAsSeesThroughDecorators(t *testing.T) {
+	inner := multipartStore{}
+	mu, ok := filestore.As[filestore.MultipartUploader](decorate(inner))
+	if !ok {
+		t.Fatal("As did not find the MultipartUploader under the decorators")
+	}
+	if mu != (filestore.MultipartUploader)(inner) {
+		t.Errorf("As returned %#v, want the inner store", mu)
+	}
+}
+
+func TestAsReportsMissingInterface(t *testing.T) {
+	if _, ok := filestore.As[filestore.MultipartUploader](decorate(stubStore{})); ok {
+		t.Error("As found a MultipartUploader in a store without one")
+	}
+}
@@ -0,0 +1,143 @@
+package filestore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/koustreak/DatRi/internal/errs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracing returns a Store that records an OpenTelemetry span, named
+// "filestore.<Method>", for every operation on store. Spans carry the
+// bucket and key where applicable; failed calls are marked as errors with
+// an "error.kind" attribute holding the errs.ErrKind.
+//
+// The GetObject span stays open until the Object is closed, so it covers
+// the download, and records the bytes read as "filestore.bytes_read".
+func WithTracing(store Store, tracer trace.Tracer) Store {
+	return &tracingStore{next: store, tracer: tracer}
+}
+
+type tracingStore struct {
+	next   Store
+	tracer trace.Tracer
+}
+
+// Unwrap returns the wrapped Store; see As.
+func (s *tracingStore) Unwrap() Store { return s.next }
+
+func (s *tracingStore) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "filestore."+op, trace.WithAttributes(attrs...))
+}
+
+// end finishes span, recording err if there is one.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("error.kind", errs.KindOf(err).String()))
+	}
+	span.End()
+}
+
+func objectAttrs(bucket, key string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("filestore.bucket", bucket),
+		attribute.String("filestore.key", key),
+	}
+}
+
+func (s *tracingStore) Ping(ctx context.Context) error {
+	ctx, span := s.start(ctx, "Ping")
+	err := s.next.Ping(ctx)
+	end(span, err)
+	return err
+}
+
+func (s *tracingStore) Close() error {
+	return s.next.Close()
+}
+
+func (s *tracingStore) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
+	ctx, span := s.start(ctx, "ListBuckets")
+	buckets, err := s.next.ListBuckets(ctx)
+	end(span, err)
+	return buckets, err
+}
+
+func (s *tracingStore) ListObjects(ctx context.Context, bucket string, opts ListOptions) ([]ObjectInfo, error) {
+	ctx, span := s.start(ctx, "ListObjects",
+		attribute.String("filestore.bucket", bucket),
+		attribute.String("filestore.prefix", opts.Prefix))
+	objects, err := s.next.ListObjects(ctx, bucket, opts)
+	if err == nil {
+		span.SetAttributes(attribute.Int("filestore.count", len(objects)))
+	}
+	end(span, err)
+	return objects, err
+}
+
+func (s *tracingStore) WalkObjects(ctx context.Context, bucket string, opts ListOptions, fn func(ObjectInfo) error) error {
+	ctx, span := s.start(ctx, "WalkObjects",
+		attribute.String("filestore.bucket", bucket),
+		attribute.String("filestore.prefix", opts.Prefix))
+	count := 0
+	err := s.next.WalkObjects(ctx, bucket, opts, func(obj ObjectInfo) error {
+		count++
+		return fn(obj)
+	})
+	span.SetAttributes(attribute.Int("filestore.count", count))
+	end(span, err)
+	return err
+}
+
+func (s *tracingStore) GetObject(ctx context.Context, bucket, key string) (Object, error) {
+	ctx, span := s.start(ctx, "GetObject", objectAttrs(bucket, key)...)
+	obj, err := s.next.GetObject(ctx, bucket, key)
+	if err != nil {
+		end(span, err)
+		return nil, err
+	}
+	return &tracedObject{Object: obj, span: span}, nil
+}
+
+func (s *tracingStore) StatObject(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	ctx, span := s.start(ctx, "StatObject", objectAttrs(bucket, key)...)
+	info, err := s.next.StatObject(ctx, bucket, key)
+	end(span, err)
+	return info, err
+}
+
+func (s *tracingStore) PresignGetURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	ctx, span := s.start(ctx, "PresignGetURL", objectAttrs(bucket, key)...)
+	url, err := s.next.PresignGetURL(ctx, bucket, key, ttl)
+	end(span, err)
+	return url, err
+}
+
+// tracedObject ends its GetObject span on Close.
+type tracedObject struct {
+	Object
+	span  trace.Span
+	read  int64
+	close sync.Once
+}
+
+func (o *tracedObject) Read(p []byte) (int, error) {
+	n, err := o.Object.Read(p)
+	o.read += int64(n)
+	return n, err
+}
+
+func (o *tracedObject) Close() error {
+	err := o.Object.Close()
+	o.close.Do(func() {
+		o.span.SetAttributes(attribute.Int64("filestore.bytes_read", o.read))
+		end(o.span, err)
+	})
+	return err
+}
@@ -0,0 +1,84 @@
+package filestore
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/koustreak/DatRi/internal/errs"
+)
+
+// GetObjectVerified opens an object like Store.GetObject and checks its
+// content against an MD5 checksum while it is read.
+//
+// expectedMD5 is the hex MD5 of the content. When it is "", the object's
+// ETag is used, which is the MD5 only for single-part uploads without
+// SSE-KMS/SSE-C encryption. Multipart ETags ("<hash>-<parts>") are not an
+// MD5 of the content, so for those an explicit checksum is required and
+// ErrKindInvalidInput is returned without one.
+//
+// Verification happens when the content has been read to the end: instead
+// of io.EOF, Read then returns an ErrKindQueryFailed error if the checksum
+// does not match. Callers must read to EOF for the check to run.
+func GetObjectVerified(ctx context.Context, store Store, bucket, key, expectedMD5 string) (Object, error) {
+	obj, err := store.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	want := strings.ToLower(expectedMD5)
+	if want == "" {
+		etag := strings.Trim(obj.Info().ETag, `"`)
+		if strings.Contains(etag, "-") {
+			obj.Close()
+			return nil, errs.New(errs.ErrKindInvalidInput,
+				fmt.Sprintf("object %s/%s was uploaded in parts; its ETag is not an MD5, pass the expected checksum", bucket, key))
+		}
+		want = strings.ToLower(etag)
+	}
+	if len(want) != 2*md5.Size {
+		obj.Close()
+		return nil, errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("expected checksum %q is not a hex MD5", want))
+	}
+	if _, err := hex.DecodeString(want); err != nil {
+		obj.Close()
+		return nil, errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("expected checksum %q is not a hex MD5", want))
+	}
+
+	return &verifyingObject{Object: obj, hash: md5.New(), want: want}, nil
+}
+
+// verifyingObject hashes everything read and checks the sum at EOF.
+type verifyingObject struct {
+	Object
+	hash hash.Hash
+	want string
+	err  error // sticky result of the EOF check
+}
+
+func (o *verifyingObject) Read(p []byte) (int, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
+
+	n, err := o.Object.Read(p)
+	o.hash.Write(p[:n])
+	if err != io.EOF {
+		return n, err
+	}
+
+	if got := hex.EncodeToString(o.hash.Sum(nil)); got != o.want {
+		info := o.Info()
+		o.err = errs.New(errs.ErrKindQueryFailed,
+			fmt.Sprintf("checksum mismatch for %s: expected MD5 %s, got %s", info.Key, o.want, got))
+	} else {
+		o.err = io.EOF
+	}
+	return n, o.err
+}
@@ -3,6 +3,8 @@ package logger
 import (
 	"net/http"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
 // HTTPMiddleware returns HTTP logging middleware
@@ -19,7 +21,7 @@ func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
 
 		// Log after request completes
 		duration := time.Since(start)
-		l.zlog.Info().
+		l.event(zerolog.InfoLevel).
 			Str("method", r.Method).
 			Str("path", r.URL.Path).
 			Str("query", r.URL.RawQuery).
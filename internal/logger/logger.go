@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -11,7 +13,28 @@ import (
 
 // Logger wraps zerolog with enterprise-grade features
 type Logger struct {
-	zlog zerolog.Logger
+	zlog  zerolog.Logger
+	level *atomicLevel // shared with child loggers created via With()
+}
+
+// atomicLevel holds a logger's minimum level so it can be changed at
+// runtime without racing against concurrent log calls.
+type atomicLevel struct {
+	v atomic.Int32
+}
+
+func newAtomicLevel(level zerolog.Level) *atomicLevel {
+	a := &atomicLevel{}
+	a.set(level)
+	return a
+}
+
+func (a *atomicLevel) get() zerolog.Level {
+	return zerolog.Level(a.v.Load())
+}
+
+func (a *atomicLevel) set(level zerolog.Level) {
+	a.v.Store(int32(level))
 }
 
 // Config holds logger configuration
@@ -38,9 +61,8 @@ func New(cfg *Config) *Logger {
 		cfg = DefaultConfig()
 	}
 
-	// Set global log level
+	// Per-logger level — changed at runtime via SetLevel, never global
 	level := parseLevel(cfg.Level)
-	zerolog.SetGlobalLevel(level)
 
 	// Configure time format
 	zerolog.TimeFieldFormat = getTimeFormat(cfg.TimeFormat)
@@ -60,32 +82,48 @@ func New(cfg *Config) *Logger {
 		zlog = zerolog.New(cfg.Output).With().Timestamp().Caller().Logger()
 	}
 
-	return &Logger{zlog: zlog}
+	return &Logger{zlog: zlog, level: newAtomicLevel(level)}
 }
 
+// SetLevel changes the minimum level of this logger (and every child logger
+// derived from it) at runtime. Safe for concurrent use.
+// Unknown level strings fall back to info, matching New.
+func (l *Logger) SetLevel(level string) {
+	l.level.set(parseLevel(level))
+}
+
+// Level returns the current minimum level (e.g. "debug", "info").
+func (l *Logger) Level() string {
+	return l.level.get().String()
+}
+
+// ctxKey is the context key under which WithContext stores a *Logger.
+type ctxKey struct{}
+
 // WithContext adds logger to context
 func (l *Logger) WithContext(ctx context.Context) context.Context {
-	return l.zlog.WithContext(ctx)
+	return context.WithValue(ctx, ctxKey{}, l)
 }
 
-// FromContext retrieves logger from context
+// FromContext retrieves logger from context. The returned logger is the one
+// stored by WithContext, so later SetLevel calls on it still apply.
 func FromContext(ctx context.Context) *Logger {
-	zlog := zerolog.Ctx(ctx)
-	if zlog.GetLevel() == zerolog.Disabled {
-		// Return default logger if not in context
-		return New(nil)
+	if l, ok := ctx.Value(ctxKey{}).(*Logger); ok {
+		return l
 	}
-	return &Logger{zlog: *zlog}
+	// Return default logger if not in context
+	return New(nil)
 }
 
 // With creates a child logger with additional fields
 func (l *Logger) With() *Context {
-	return &Context{ctx: l.zlog.With()}
+	return &Context{ctx: l.zlog.With(), level: l.level}
 }
 
 // Context wraps zerolog.Context for field chaining
 type Context struct {
-	ctx zerolog.Context
+	ctx   zerolog.Context
+	level *atomicLevel
 }
 
 func (c *Context) Str(key, val string) *Context {
@@ -109,53 +147,53 @@ func (c *Context) Any(key string, val interface{}) *Context {
 }
 
 func (c *Context) Logger() *Logger {
-	return &Logger{zlog: c.ctx.Logger()}
+	return &Logger{zlog: c.ctx.Logger(), level: c.level}
 }
 
 // Logging methods
 func (l *Logger) Debug(msg string) {
-	l.zlog.Debug().Msg(msg)
+	l.event(zerolog.DebugLevel).Msg(msg)
 }
 
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	l.zlog.Debug().Msgf(format, args...)
+	l.event(zerolog.DebugLevel).Msgf(format, args...)
 }
 
 func (l *Logger) Info(msg string) {
-	l.zlog.Info().Msg(msg)
+	l.event(zerolog.InfoLevel).Msg(msg)
 }
 
 func (l *Logger) Infof(format string, args ...interface{}) {
-	l.zlog.Info().Msgf(format, args...)
+	l.event(zerolog.InfoLevel).Msgf(format, args...)
 }
 
 func (l *Logger) Warn(msg string) {
-	l.zlog.Warn().Msg(msg)
+	l.event(zerolog.WarnLevel).Msg(msg)
 }
 
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	l.zlog.Warn().Msgf(format, args...)
+	l.event(zerolog.WarnLevel).Msgf(format, args...)
 }
 
 func (l *Logger) Error(msg string) {
-	l.zlog.Error().Msg(msg)
+	l.event(zerolog.ErrorLevel).Msg(msg)
 }
 
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	l.zlog.Error().Msgf(format, args...)
+	l.event(zerolog.ErrorLevel).Msgf(format, args...)
 }
 
 func (l *Logger) Fatal(msg string) {
-	l.zlog.Fatal().Msg(msg)
+	l.event(zerolog.FatalLevel).Msg(msg)
 }
 
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	l.zlog.Fatal().Msgf(format, args...)
+	l.event(zerolog.FatalLevel).Msgf(format, args...)
 }
 
 // Structured logging with fields
 func (l *Logger) InfoWith(msg string, fields map[string]interface{}) {
-	event := l.zlog.Info()
+	event := l.event(zerolog.InfoLevel)
 	for k, v := range fields {
 		event = event.Interface(k, v)
 	}
@@ -163,7 +201,28 @@ func (l *Logger) InfoWith(msg string, fields map[string]interface{}) {
 }
 
 func (l *Logger) ErrorWith(msg string, err error, fields map[string]interface{}) {
-	event := l.zlog.Error().Err(err)
+	event := l.event(zerolog.ErrorLevel).Err(err)
+	for k, v := range fields {
+		event = event.Interface(k, v)
+	}
+	event.Msg(msg)
+}
+
+// Log writes msg at a level chosen at runtime (e.g. proxied from another
+// system). Level matching is case-insensitive and accepts "warning" as an
+// alias for "warn". Unknown levels are dropped silently.
+// Unlike Fatal, logging at "fatal" here never exits the process.
+func (l *Logger) Log(level string, msg string) {
+	l.LogWith(level, msg, nil)
+}
+
+// LogWith is Log with additional structured fields.
+func (l *Logger) LogWith(level string, msg string, fields map[string]interface{}) {
+	lvl, ok := lookupLevel(level)
+	if !ok || lvl < l.level.get() {
+		return
+	}
+	event := l.zlog.WithLevel(lvl)
 	for k, v := range fields {
 		event = event.Interface(k, v)
 	}
@@ -172,24 +231,56 @@ func (l *Logger) ErrorWith(msg string, err error, fields map[string]interface{})
 
 // HTTP middleware helper
 func (l *Logger) HTTPEvent() *zerolog.Event {
-	return l.zlog.Info()
+	return l.event(zerolog.InfoLevel)
+}
+
+// event returns a zerolog event for level, or nil when the level is below
+// the logger's current minimum. zerolog events are nil-safe, so callers can
+// chain fields and call Msg unconditionally.
+func (l *Logger) event(level zerolog.Level) *zerolog.Event {
+	if level < l.level.get() {
+		return nil
+	}
+	switch level {
+	case zerolog.DebugLevel:
+		return l.zlog.Debug()
+	case zerolog.InfoLevel:
+		return l.zlog.Info()
+	case zerolog.WarnLevel:
+		return l.zlog.Warn()
+	case zerolog.ErrorLevel:
+		return l.zlog.Error()
+	case zerolog.FatalLevel:
+		return l.zlog.Fatal()
+	default:
+		return l.zlog.WithLevel(level)
+	}
 }
 
 // Helper functions
 func parseLevel(level string) zerolog.Level {
-	switch level {
+	if lvl, ok := lookupLevel(level); ok {
+		return lvl
+	}
+	return zerolog.InfoLevel
+}
+
+// lookupLevel maps a level name to a zerolog.Level, reporting whether the
+// name was recognised.
+func lookupLevel(level string) (zerolog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
 	case "debug":
-		return zerolog.DebugLevel
+		return zerolog.DebugLevel, true
 	case "info":
-		return zerolog.InfoLevel
-	case "warn":
-		return zerolog.WarnLevel
+		return zerolog.InfoLevel, true
+	case "warn", "warning":
+		return zerolog.WarnLevel, true
 	case "error":
-		return zerolog.ErrorLevel
+		return zerolog.ErrorLevel, true
 	case "fatal":
-		return zerolog.FatalLevel
+		return zerolog.FatalLevel, true
 	default:
-		return zerolog.InfoLevel
+		return zerolog.NoLevel, false
 	}
 }
 
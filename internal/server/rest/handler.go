@@ -42,6 +42,7 @@ func (h *handlers) handleListTables(w http.ResponseWriter, r *http.Request) {
 //
 //	GET /users
 //	GET /users?limit=20&offset=0&order_by=created_at&order_dir=desc&name=alice
+//	GET /users?age[gte]=18&role[in]=admin,owner&order_by=last_name,created_at:desc
 //	→ 200 { "data": [...], "meta": { "count": 20, "limit": 20, "offset": 0 } }
 func (h *handlers) handleListRows(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -171,6 +172,8 @@ func httpErrFromDatri(w http.ResponseWriter, err error) {
 		writeError(w, http.StatusBadRequest, "invalid_input", err.Error())
 	case errs.IsTimeout(err):
 		writeError(w, http.StatusGatewayTimeout, "timeout", err.Error())
+	case errs.IsConflict(err):
+		writeError(w, http.StatusConflict, "conflict", err.Error())
 	case errs.IsPermissionDenied(err):
 		writeError(w, http.StatusForbidden, "permission_denied", err.Error())
 	case errs.IsConnectionFailed(err):
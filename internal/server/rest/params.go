@@ -3,6 +3,7 @@ package rest
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -11,23 +12,48 @@ import (
 )
 
 // QueryParams holds the parsed, validated values from the URL query string.
-// Option-A style: ?limit=20&offset=0&order_by=id&order_dir=desc&name=alice&age=30
+// Option-A style:
+//
+//	?limit=20&offset=0&order_by=created_at:desc,id&name=alice&age[gt]=30&role[in]=admin,owner
 type QueryParams struct {
-	Limit    *int
-	Offset   *int
-	OrderBy  string
-	OrderDir database.SortDirection // Asc | Desc
+	Limit  *int
+	Offset *int
 
-	// Filters holds col→value pairs extracted from all remaining query params.
-	// Each becomes a WHERE col = value clause (equality only for now).
+	// OrderBy lists the sort keys in priority order.
+	OrderBy []orderKey
+
+	// Filters holds the column filters extracted from all remaining query
+	// params, combined with AND.
 	Filters []filterClause
 }
 
+type orderKey struct {
+	Column string
+	Dir    database.SortDirection
+}
+
+// filterClause is one `col[op]=value` filter. Op is a key of filterOps;
+// a bare `col=value` is "eq".
 type filterClause struct {
 	Column string
+	Op     string
 	Value  string
 }
 
+// filterOps maps the operator names accepted in `col[op]=value` to SQL
+// comparison operators. "in" is handled separately: its value is a
+// comma-separated list.
+var filterOps = map[string]string{
+	"eq":   "=",
+	"neq":  "!=",
+	"lt":   "<",
+	"lte":  "<=",
+	"gt":   ">",
+	"gte":  ">=",
+	"like": "LIKE",
+	"in":   "",
+}
+
 // reservedParams is the set of query keys consumed by the framework.
 // Everything else is treated as a column filter.
 var reservedParams = map[string]bool{
@@ -38,9 +64,8 @@ var reservedParams = map[string]bool{
 }
 
 // parseQueryParams reads the URL query string and returns a validated
-// QueryParams. It never returns a hard error — invalid values are silently
-// ignored and defaults are applied, matching the principle of least surprise
-// for an auto-generated API.
+// QueryParams. Malformed values, unknown columns and unknown operators are
+// rejected with ErrKindInvalidInput.
 func parseQueryParams(r *http.Request, table *database.TableInfo) (*QueryParams, error) {
 	q := r.URL.Query()
 	p := &QueryParams{}
@@ -68,41 +93,82 @@ func parseQueryParams(r *http.Request, table *database.TableInfo) (*QueryParams,
 		p.Offset = &n
 	}
 
-	// --- order_by ---
-	if col := q.Get("order_by"); col != "" {
-		if table != nil && !columnExists(table, col) {
-			return nil, errs.New(errs.ErrKindInvalidInput,
-				fmt.Sprintf("unknown column for order_by: %q", col))
+	// --- order_by / order_dir ---
+	// order_by is a comma-separated list of col or col:asc|desc. order_dir
+	// is the default direction for keys that do not name one.
+	defaultDir := database.Asc
+	if strings.EqualFold(q.Get("order_dir"), "desc") {
+		defaultDir = database.Desc
+	}
+	if raw := q.Get("order_by"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			col, dirName, hasDir := strings.Cut(strings.TrimSpace(part), ":")
+			dir := defaultDir
+			if hasDir {
+				switch strings.ToLower(dirName) {
+				case "asc":
+					dir = database.Asc
+				case "desc":
+					dir = database.Desc
+				default:
+					return nil, errs.New(errs.ErrKindInvalidInput,
+						fmt.Sprintf("order_by direction must be asc or desc, got %q", dirName))
+				}
+			}
+			if table != nil && !columnExists(table, col) {
+				return nil, errs.New(errs.ErrKindInvalidInput,
+					fmt.Sprintf("unknown column for order_by: %q", col))
+			}
+			p.OrderBy = append(p.OrderBy, orderKey{Column: col, Dir: dir})
 		}
-		p.OrderBy = col
 	}
 
-	// --- order_dir ---
-	switch strings.ToLower(q.Get("order_dir")) {
-	case "desc":
-		p.OrderDir = database.Desc
-	default:
-		p.OrderDir = database.Asc
+	// --- column filters (everything that is not a reserved key) ---
+	// Keys are sorted so the generated SQL is stable across requests.
+	keys := make([]string, 0, len(q))
+	for key := range q {
+		if !reservedParams[key] {
+			keys = append(keys, key)
+		}
 	}
+	sort.Strings(keys)
 
-	// --- column filters (everything that is not a reserved key) ---
-	for key, vals := range q {
-		if reservedParams[key] {
-			continue
+	for _, key := range keys {
+		col, op, err := parseFilterKey(key)
+		if err != nil {
+			return nil, err
 		}
 		// Validate that the column actually exists in the schema to prevent
 		// arbitrary SQL injection through the column name position.
-		if table != nil && !columnExists(table, key) {
+		if table != nil && !columnExists(table, col) {
 			return nil, errs.New(errs.ErrKindInvalidInput,
-				fmt.Sprintf("unknown filter column: %q", key))
+				fmt.Sprintf("unknown filter column: %q", col))
 		}
-		// Use only the first value for simplicity (multi-value = future work).
-		p.Filters = append(p.Filters, filterClause{Column: key, Value: vals[0]})
+		// Use only the first value for simplicity; "in" takes a list instead.
+		p.Filters = append(p.Filters, filterClause{Column: col, Op: op, Value: q[key][0]})
 	}
 
 	return p, nil
 }
 
+// parseFilterKey splits a filter key of the form col or col[op].
+func parseFilterKey(key string) (col, op string, err error) {
+	open := strings.IndexByte(key, '[')
+	if open < 0 {
+		return key, "eq", nil
+	}
+	if !strings.HasSuffix(key, "]") {
+		return "", "", errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("malformed filter %q: expected col[op]", key))
+	}
+	col, op = key[:open], strings.ToLower(key[open+1:len(key)-1])
+	if _, ok := filterOps[op]; !ok {
+		return "", "", errs.New(errs.ErrKindInvalidInput,
+			fmt.Sprintf("unknown filter operator %q in %q", op, key))
+	}
+	return col, op, nil
+}
+
 // columnExists reports whether the table has a column with the given name.
 func columnExists(t *database.TableInfo, name string) bool {
 	for _, c := range t.Columns {
@@ -120,10 +186,14 @@ const maxLimit = 1000
 // applyToBuilder applies the parsed query params onto a SelectBuilder.
 func (p *QueryParams) applyToBuilder(b *database.SelectBuilder) *database.SelectBuilder {
 	for _, f := range p.Filters {
-		b = b.Where(f.Column, "=", f.Value)
+		if f.Op == "in" {
+			b = b.WhereAny(f.Column, "=", strings.Split(f.Value, ","))
+			continue
+		}
+		b = b.Where(f.Column, filterOps[f.Op], f.Value)
 	}
-	if p.OrderBy != "" {
-		b = b.OrderBy(p.OrderBy, p.OrderDir)
+	for _, o := range p.OrderBy {
+		b = b.OrderBy(o.Column, o.Dir)
 	}
 	if p.Limit != nil {
 		b = b.Limit(*p.Limit)